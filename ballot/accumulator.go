@@ -0,0 +1,129 @@
+package ballot
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// Accumulator sums the cipherfields of many ballots field by field. The
+// result encrypts the tally of each field, which can be decrypted without
+// decrypting any individual ballot.
+type Accumulator struct {
+	fields  []*elgamal.Ciphertext
+	ballots int
+}
+
+// NewAccumulator returns an empty accumulator for ballots of the default
+// ballot_proof circuit.
+func NewAccumulator() *Accumulator {
+	return defaultCircuit.NewAccumulator()
+}
+
+// NewAccumulator returns an empty accumulator for ballots of the circuit.
+func (c *Circuit) NewAccumulator() *Accumulator {
+	fields := make([]*elgamal.Ciphertext, c.nFields)
+	for i := range fields {
+		fields[i] = elgamal.NewZeroCiphertext()
+	}
+	return &Accumulator{fields: fields}
+}
+
+// Add adds the cipherfields of a ballot to the tally.
+func (a *Accumulator) Add(cipherfields [][2][2]*big.Int) error {
+	return a.AddWeighted(cipherfields, 1)
+}
+
+// AddWeighted adds the cipherfields of a ballot multiplied by weight to the
+// tally.
+func (a *Accumulator) AddWeighted(cipherfields [][2][2]*big.Int, weight uint64) error {
+	fields, err := a.combine(cipherfields, weight, (*elgamal.Ciphertext).Add)
+	if err != nil {
+		return err
+	}
+	a.fields = fields
+	a.ballots++
+	return nil
+}
+
+// Sub removes the cipherfields of a ballot from the tally, such as a ballot
+// overwritten by a later vote of the same voter.
+func (a *Accumulator) Sub(cipherfields [][2][2]*big.Int) error {
+	return a.SubWeighted(cipherfields, 1)
+}
+
+// SubWeighted removes the cipherfields of a ballot added with AddWeighted
+// from the tally.
+func (a *Accumulator) SubWeighted(cipherfields [][2][2]*big.Int, weight uint64) error {
+	if a.ballots == 0 {
+		return fmt.Errorf("no ballots to remove")
+	}
+	fields, err := a.combine(cipherfields, weight, (*elgamal.Ciphertext).Sub)
+	if err != nil {
+		return err
+	}
+	a.fields = fields
+	a.ballots--
+	return nil
+}
+
+// Ballots returns the number of ballots in the tally.
+func (a *Accumulator) Ballots() int {
+	return a.ballots
+}
+
+// Fields returns the encrypted tally of each field.
+func (a *Accumulator) Fields() []*elgamal.Ciphertext {
+	return append([]*elgamal.Ciphertext{}, a.fields...)
+}
+
+// Cipherfields returns the encrypted tally in the cipherfields layout of the
+// ballot inputs.
+func (a *Accumulator) Cipherfields() [][2][2]*big.Int {
+	cf := make([][2][2]*big.Int, len(a.fields))
+	for i, f := range a.fields {
+		cf[i] = [2][2]*big.Int{f.C1, f.C2}
+	}
+	return cf
+}
+
+// Decrypt decrypts the tally of each field with the private key and the
+// discrete log table, which must cover the largest field tally.
+func (a *Accumulator) Decrypt(priv *big.Int, table *elgamal.DLogTable) ([]uint64, error) {
+	results := make([]uint64, len(a.fields))
+	for i, f := range a.fields {
+		var err error
+		if results[i], err = f.Decrypt(priv, table); err != nil {
+			return nil, fmt.Errorf("failed to decrypt field %d: %w", i, err)
+		}
+	}
+	return results, nil
+}
+
+// combine applies op to each field of the tally and the weighted
+// cipherfields, leaving the tally untouched on error.
+func (a *Accumulator) combine(cipherfields [][2][2]*big.Int, weight uint64,
+	op func(*elgamal.Ciphertext, *elgamal.Ciphertext) (*elgamal.Ciphertext, error),
+) ([]*elgamal.Ciphertext, error) {
+	if len(cipherfields) != len(a.fields) {
+		return nil, fmt.Errorf("invalid number of cipherfields: got %d, want %d", len(cipherfields), len(a.fields))
+	}
+	scalar := new(big.Int).SetUint64(weight)
+	fields := make([]*elgamal.Ciphertext, len(a.fields))
+	for i, cf := range cipherfields {
+		ct := elgamal.NewCiphertext(cf[0], cf[1])
+		if weight != 1 {
+			var err error
+			if ct, err = ct.Mul(scalar); err != nil {
+				return nil, fmt.Errorf("invalid cipherfield %d: %w", i, err)
+			}
+		}
+		sum, err := op(a.fields[i], ct)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cipherfield %d: %w", i, err)
+		}
+		fields[i] = sum
+	}
+	return fields, nil
+}
@@ -0,0 +1,257 @@
+// Package ballot builds the inputs of the ballot_proof circuit from a ballot
+// mode, the process and voter data, the encryption key and the chosen field
+// values, computing the cipherfields, the vote ID and the inputs hash as the
+// circuit does.
+package ballot
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// NFields is the number of fields of the default ballot_proof circuit, the
+// n_fields parameter of BallotProof. Circuits compiled with other values are
+// described by a Circuit.
+const NFields = 8
+
+// Mode is the ballot mode of a voting process, which sets the rules checked
+// by the BallotChecker template.
+type Mode struct {
+	NumFields      int
+	UniqueValues   bool
+	MaxValue       uint64
+	MinValue       uint64
+	MaxValueSum    uint64
+	MinValueSum    uint64
+	CostExponent   int
+	CostFromWeight bool
+}
+
+// Ballot holds the data a voter needs to build the inputs of a ballot proof.
+type Ballot struct {
+	Mode      Mode
+	ProcessID *big.Int
+	Address   *big.Int
+	Weight    uint64
+	// EncryptionKey is the public key of the process in TE coordinates.
+	EncryptionKey [2]*big.Int
+	// K is the secret the encryption randomness and the vote ID derive from.
+	// A random one is used if it is nil.
+	K *big.Int
+	// Fields are the chosen values, padded with zeros to the circuit fields.
+	Fields []uint64
+}
+
+// Inputs holds the inputs of the ballot_proof circuit.
+type Inputs struct {
+	Mode          Mode
+	ProcessID     *big.Int
+	Address       *big.Int
+	Weight        uint64
+	EncryptionKey [2]*big.Int
+	K             *big.Int
+	Fields        []uint64
+	Cipherfields  [][2][2]*big.Int
+	VoteID        *big.Int
+	InputsHash    *big.Int
+}
+
+// RandomK returns a random secret in the BN254 scalar field, read from r. If
+// r is nil, crypto/rand is used.
+func RandomK(r io.Reader) (*big.Int, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	k, err := rand.Int(r, fr.Modulus())
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate k: %w", err)
+	}
+	return k, nil
+}
+
+// BuildInputs encrypts the ballot fields and computes the vote ID and the
+// inputs hash, returning the inputs of the default ballot_proof circuit.
+func BuildInputs(b *Ballot) (*Inputs, error) {
+	return defaultCircuit.BuildInputs(b)
+}
+
+// BuildInputs encrypts the ballot fields and computes the vote ID and the
+// inputs hash, returning the inputs of the circuit.
+func (c *Circuit) BuildInputs(b *Ballot) (*Inputs, error) {
+	if err := b.validate(c.nFields); err != nil {
+		return nil, err
+	}
+	k := b.K
+	if k == nil {
+		var err error
+		if k, err = RandomK(nil); err != nil {
+			return nil, err
+		}
+	}
+	fields := make([]uint64, c.nFields)
+	copy(fields, b.Fields)
+
+	ks, err := DerivePoseidonChain(k, c.nFields)
+	if err != nil {
+		return nil, err
+	}
+	cipherfields := make([][2][2]*big.Int, c.nFields)
+	for i := range fields {
+		c1, c2, err := elgamal.Encrypt(new(big.Int).SetUint64(fields[i]), b.EncryptionKey[0], b.EncryptionKey[1], ks[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt field %d: %w", i, err)
+		}
+		cipherfields[i] = [2][2]*big.Int{c1, c2}
+	}
+	voteID, err := VoteID(b.ProcessID, b.Address, k)
+	if err != nil {
+		return nil, err
+	}
+	inputs := &Inputs{
+		Mode:          b.Mode,
+		ProcessID:     new(big.Int).Set(b.ProcessID),
+		Address:       new(big.Int).Set(b.Address),
+		Weight:        b.Weight,
+		EncryptionKey: [2]*big.Int{new(big.Int).Set(b.EncryptionKey[0]), new(big.Int).Set(b.EncryptionKey[1])},
+		K:             new(big.Int).Set(k),
+		Fields:        fields,
+		Cipherfields:  cipherfields,
+		VoteID:        voteID,
+	}
+	if inputs.InputsHash, err = inputs.ComputeInputsHash(); err != nil {
+		return nil, err
+	}
+	return inputs, nil
+}
+
+// validate checks that the ballot can be encoded as the inputs of a circuit
+// with nFields fields.
+func (b *Ballot) validate(nFields int) error {
+	for _, v := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"process ID", b.ProcessID},
+		{"address", b.Address},
+		{"encryption key x", b.EncryptionKey[0]},
+		{"encryption key y", b.EncryptionKey[1]},
+	} {
+		if v.value == nil {
+			return fmt.Errorf("missing %s", v.name)
+		}
+		if v.value.Sign() < 0 {
+			return fmt.Errorf("%s is negative", v.name)
+		}
+	}
+	if err := elgamal.ValidatePublicKey(b.EncryptionKey[0], b.EncryptionKey[1]); err != nil {
+		return fmt.Errorf("invalid encryption key: %w", err)
+	}
+	if b.K != nil && b.K.Sign() < 0 {
+		return fmt.Errorf("k is negative")
+	}
+	if len(b.Fields) > nFields {
+		return fmt.Errorf("too many fields: got %d, max %d", len(b.Fields), nFields)
+	}
+	for i, v := range b.Fields {
+		if v > maxFieldValue {
+			return fmt.Errorf("field %d value %d does not fit in the %d-bit messages of ElGamal", i, v, elgamal.MsgBits)
+		}
+	}
+	if b.Mode.NumFields < 0 || b.Mode.NumFields > nFields {
+		return fmt.Errorf("invalid number of fields in ballot mode: got %d, max %d", b.Mode.NumFields, nFields)
+	}
+	return nil
+}
+
+// hashInputs returns the inputs hashed into inputs_hash, in the order of
+// ballot_proof.circom.
+func (in *Inputs) hashInputs() []*big.Int {
+	list := []*big.Int{
+		in.ProcessID,
+		big.NewInt(int64(in.Mode.NumFields)),
+		boolToInt(in.Mode.UniqueValues),
+		new(big.Int).SetUint64(in.Mode.MaxValue),
+		new(big.Int).SetUint64(in.Mode.MinValue),
+		new(big.Int).SetUint64(in.Mode.MaxValueSum),
+		new(big.Int).SetUint64(in.Mode.MinValueSum),
+		big.NewInt(int64(in.Mode.CostExponent)),
+		boolToInt(in.Mode.CostFromWeight),
+		in.EncryptionKey[0],
+		in.EncryptionKey[1],
+		in.Address,
+		in.VoteID,
+	}
+	for _, cf := range in.Cipherfields {
+		list = append(list, cf[0][0], cf[0][1], cf[1][0], cf[1][1])
+	}
+	return append(list, new(big.Int).SetUint64(in.Weight))
+}
+
+// ComputeInputsHash returns the inputs_hash of the inputs, the MultiHash of
+// all the inputs that could be public.
+func (in *Inputs) ComputeInputsHash() (*big.Int, error) {
+	return MultiHash(in.hashInputs())
+}
+
+// PublicSignals returns the public signals of the ballot proof for the
+// inputs as snarkjs lists them: address, vote_id and inputs_hash, whatever
+// the circuit fields.
+func (in *Inputs) PublicSignals() []string {
+	return []string{in.Address.String(), in.VoteID.String(), in.InputsHash.String()}
+}
+
+// Map returns the inputs keyed by the signal names of ballot_proof.circom.
+func (in *Inputs) Map() map[string]any {
+	return map[string]any{
+		"fields":            in.Fields,
+		"weight":            in.Weight,
+		"encryption_pubkey": []string{in.EncryptionKey[0].String(), in.EncryptionKey[1].String()},
+		"cipherfields":      StringifyCipherfields(in.Cipherfields),
+		"process_id":        in.ProcessID.String(),
+		"address":           in.Address.String(),
+		"k":                 in.K.String(),
+		"vote_id":           in.VoteID.String(),
+		"inputs_hash":       in.InputsHash.String(),
+		"num_fields":        in.Mode.NumFields,
+		"unique_values":     boolToInt(in.Mode.UniqueValues).Int64(),
+		"max_value":         in.Mode.MaxValue,
+		"min_value":         in.Mode.MinValue,
+		"max_value_sum":     in.Mode.MaxValueSum,
+		"min_value_sum":     in.Mode.MinValueSum,
+		"cost_exponent":     in.Mode.CostExponent,
+		"cost_from_weight":  boolToInt(in.Mode.CostFromWeight).Int64(),
+	}
+}
+
+// MarshalJSON encodes the inputs as the input JSON of the circuit.
+func (in *Inputs) MarshalJSON() ([]byte, error) {
+	return json.Marshal(in.Map())
+}
+
+// StringifyCipherfields converts cipherfields to decimal strings for circom
+// inputs.
+func StringifyCipherfields(cf [][2][2]*big.Int) [][2][2]string {
+	out := make([][2][2]string, len(cf))
+	for i := range cf {
+		for j := 0; j < 2; j++ {
+			for k := 0; k < 2; k++ {
+				out[i][j][k] = cf[i][j][k].String()
+			}
+		}
+	}
+	return out
+}
+
+func boolToInt(b bool) *big.Int {
+	if b {
+		return big.NewInt(1)
+	}
+	return big.NewInt(0)
+}
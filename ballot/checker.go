@@ -0,0 +1,182 @@
+package ballot
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Rule names a ballot mode rule enforced by the BallotChecker template,
+// after the circuit input that sets it.
+type Rule string
+
+const (
+	RuleNumFields    Rule = "num_fields"
+	RuleUniqueValues Rule = "unique_values"
+	RuleMaxValue     Rule = "max_value"
+	RuleMinValue     Rule = "min_value"
+	RuleMaxValueSum  Rule = "max_value_sum"
+	RuleWeight       Rule = "weight"
+	RuleMinValueSum  Rule = "min_value_sum"
+	RuleCostExponent Rule = "cost_exponent"
+)
+
+// CheckError is a violation of a ballot mode rule. Field is the index of the
+// offending field, or -1 if the rule applies to the whole ballot.
+type CheckError struct {
+	Rule   Rule
+	Field  int
+	Reason string
+}
+
+func (e *CheckError) Error() string {
+	if e.Field < 0 {
+		return fmt.Sprintf("ballot violates %s: %s", e.Rule, e.Reason)
+	}
+	return fmt.Sprintf("field %d violates %s: %s", e.Field, e.Rule, e.Reason)
+}
+
+// CheckErrors lists all the rules a ballot violates.
+type CheckErrors []*CheckError
+
+func (e CheckErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// comparatorBits is the size of the comparators of BallotChecker on costs,
+// and boundBits the size of those on field values.
+const (
+	comparatorBits = 128
+	boundBits      = 252
+)
+
+// CheckFields evaluates the BallotChecker template of the default circuit
+// natively on the fields, as described in Circuit.CheckFields.
+func CheckFields(mode Mode, fields []uint64, weight uint64) error {
+	return defaultCircuit.CheckFields(mode, fields, weight)
+}
+
+// CheckFields evaluates the BallotChecker template natively on the fields,
+// padded with zeros to the circuit fields, and returns CheckErrors with every
+// violated rule, or nil if the fields satisfy them. It does not check that
+// the fields fit in the msg_bits of ElGamal, which BuildInputs rejects.
+func (c *Circuit) CheckFields(mode Mode, fields []uint64, weight uint64) error {
+	if len(fields) > c.nFields {
+		return CheckErrors{{Rule: RuleNumFields, Field: -1, Reason: fmt.Sprintf("got %d fields, max %d", len(fields), c.nFields)}}
+	}
+	padded := make([]*big.Int, c.nFields)
+	for i := range padded {
+		padded[i] = new(big.Int)
+		if i < len(fields) {
+			padded[i].SetUint64(fields[i])
+		}
+	}
+	numFields := big.NewInt(int64(mode.NumFields))
+	if mode.NumFields < 0 {
+		numFields.Mod(numFields, fr.Modulus())
+	}
+
+	var errs CheckErrors
+	// MaskGenerator
+	if lt, ok := circomLessThan(boundBits, numFields, big.NewInt(int64(c.nFields)+1)); !ok || !lt {
+		return append(errs, &CheckError{Rule: RuleNumFields, Field: -1, Reason: fmt.Sprintf("num_fields %d is greater than %d", mode.NumFields, c.nFields)})
+	}
+	mask := make([]bool, c.nFields)
+	for i := range mask {
+		mask[i] = i < mode.NumFields
+	}
+
+	// UniqueArray
+	if mode.UniqueValues {
+		for j := range padded {
+			for i := 0; i < j; i++ {
+				if mask[i] && mask[j] && padded[i].Cmp(padded[j]) == 0 {
+					errs = append(errs, &CheckError{Rule: RuleUniqueValues, Field: j, Reason: fmt.Sprintf("value %s repeats field %d", padded[j], i)})
+					break
+				}
+			}
+		}
+	}
+
+	// ArrayInBounds
+	maxValue, minValue := new(big.Int).SetUint64(mode.MaxValue), new(big.Int).SetUint64(mode.MinValue)
+	for i, v := range padded {
+		if !mask[i] {
+			continue
+		}
+		if gt, ok := circomLessThan(boundBits, maxValue, v); !ok || gt {
+			errs = append(errs, &CheckError{Rule: RuleMaxValue, Field: i, Reason: fmt.Sprintf("value %s is greater than %d", v, mode.MaxValue)})
+		}
+		if lt, ok := circomLessThan(boundBits, v, minValue); !ok || lt {
+			errs = append(errs, &CheckError{Rule: RuleMinValue, Field: i, Reason: fmt.Sprintf("value %s is less than %d", v, mode.MinValue)})
+		}
+	}
+
+	// SumPow, over the masked fields, with a 128-bit exponent
+	if mode.CostExponent < 0 {
+		return append(errs, &CheckError{Rule: RuleCostExponent, Field: -1, Reason: fmt.Sprintf("negative exponent %d", mode.CostExponent)})
+	}
+	cost := totalCost(mode, padded)
+
+	// the cost is bounded by max_value_sum, or by the weight if
+	// cost_from_weight is set, unless max_value_sum is 0
+	rule, limit := RuleMaxValueSum, new(big.Int).SetUint64(mode.MaxValueSum)
+	if mode.CostFromWeight {
+		rule, limit = RuleWeight, new(big.Int).SetUint64(weight)
+	}
+	le, ok := circomLessThan(comparatorBits, cost, new(big.Int).Add(limit, big.NewInt(1)))
+	switch {
+	case !ok:
+		errs = append(errs, &CheckError{Rule: rule, Field: -1, Reason: fmt.Sprintf("total cost %s overflows %d bits", cost, comparatorBits)})
+	case mode.MaxValueSum > 0 && !le:
+		errs = append(errs, &CheckError{Rule: rule, Field: -1, Reason: fmt.Sprintf("total cost %s is greater than %s", cost, limit)})
+	}
+
+	// the cost must reach min_value_sum
+	minValueSum := new(big.Int).SetUint64(mode.MinValueSum)
+	if gt, ok := circomLessThan(comparatorBits, minValueSum, new(big.Int).Add(cost, big.NewInt(1))); !ok || !gt {
+		errs = append(errs, &CheckError{Rule: RuleMinValueSum, Field: -1, Reason: fmt.Sprintf("total cost %s is less than %d", cost, mode.MinValueSum)})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// totalCost returns the cost of the ballot computed by SumPow: the sum of the
+// first num_fields fields raised to cost_exponent, in the BN254 scalar field.
+// The fields are padded with zeros to the circuit fields, as a padded zero
+// costs 0^0 = 1 with a cost exponent of 0. The exponent must not be negative.
+func totalCost(mode Mode, padded []*big.Int) *big.Int {
+	cost, exp := new(big.Int), big.NewInt(int64(mode.CostExponent))
+	for i := 0; i < mode.NumFields; i++ {
+		cost.Add(cost, new(big.Int).Exp(padded[i], exp, fr.Modulus()))
+	}
+	return cost.Mod(cost, fr.Modulus())
+}
+
+// circomLessThan evaluates the circomlib LessThan(n) comparator on a and b.
+// It returns false in ok if the constraints cannot be satisfied, when the
+// operands do not fit in n bits.
+func circomLessThan(n uint, a, b *big.Int) (lt, ok bool) {
+	// Num2Bits(n+1) of a + 2^n - b, and out = 1 - bit n
+	v := new(big.Int).Lsh(big.NewInt(1), n)
+	v.Add(v, a).Sub(v, b).Mod(v, fr.Modulus())
+	if v.BitLen() > int(n)+1 {
+		return false, false
+	}
+	return v.Bit(int(n)) == 0, true
+}
+
+// Check evaluates the BallotChecker rules of the default circuit on the
+// ballot fields, as described in Circuit.CheckFields.
+func (b *Ballot) Check() error {
+	return CheckFields(b.Mode, b.Fields, b.Weight)
+}
@@ -0,0 +1,41 @@
+package ballot
+
+import "fmt"
+
+// staticHashInputs is the number of inputs of inputs_hash besides the
+// cipherfields: the process ID, the eight ballot mode values, the encryption
+// key, the address, the vote ID and the weight.
+const staticHashInputs = 14
+
+// MaxNFields is the largest n_fields whose inputs_hash inputs fit in
+// MultiHash.
+const MaxNFields = (MaxHashInputs - staticHashInputs) / 4
+
+// Circuit describes a ballot_proof circuit compiled as BallotProof(n_fields).
+type Circuit struct {
+	nFields int
+}
+
+// defaultCircuit is the ballot_proof circuit compiled with NFields fields.
+var defaultCircuit = &Circuit{nFields: NFields}
+
+// NewCircuit returns the ballot_proof circuit compiled with nFields fields,
+// which must be between 1 and MaxNFields.
+func NewCircuit(nFields int) (*Circuit, error) {
+	if nFields < 1 || nFields > MaxNFields {
+		return nil, fmt.Errorf("invalid number of circuit fields: got %d, want 1 to %d (%d hash inputs)",
+			nFields, MaxNFields, HashInputsLen(nFields))
+	}
+	return &Circuit{nFields: nFields}, nil
+}
+
+// NFields returns the n_fields parameter of the circuit.
+func (c *Circuit) NFields() int {
+	return c.nFields
+}
+
+// HashInputsLen returns the number of inputs hashed into inputs_hash by a
+// circuit with nFields fields, 14 + 4*nFields.
+func HashInputsLen(nFields int) int {
+	return staticHashInputs + 4*nFields
+}
@@ -0,0 +1,407 @@
+package ballot
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+)
+
+// maxCountStates bounds the work of the dynamic programming used to count
+// the ballots of a mode: the number of cost values tracked times the fields
+// times the classes of values with distinct costs.
+const maxCountStates = 1 << 22
+
+// CountBallots counts the ballots accepted by the ballot mode in the default
+// circuit, as described in Circuit.CountBallots.
+func CountBallots(mode Mode, weight uint64) (*big.Int, error) {
+	return defaultCircuit.CountBallots(mode, weight)
+}
+
+// CountBallots counts the vectors of num_fields values that the BallotChecker
+// rules accept for the ballot mode and the voter weight, with dynamic
+// programming over the cost of the ballot. The mode must pass ValidateMode,
+// and its cost bounds must be small enough for the tables to fit in memory.
+func (c *Circuit) CountBallots(mode Mode, weight uint64) (*big.Int, error) {
+	s, err := c.newBallotSpace(mode, weight)
+	if err != nil {
+		return nil, err
+	}
+	return s.count(), nil
+}
+
+// EnumerateBallots lists the ballots accepted by the ballot mode in the
+// default circuit, as described in Circuit.EnumerateBallots.
+func EnumerateBallots(mode Mode, weight uint64, limit int) ([][]uint64, error) {
+	return defaultCircuit.EnumerateBallots(mode, weight, limit)
+}
+
+// EnumerateBallots lists in lexicographic order the vectors of num_fields
+// values that the BallotChecker rules accept for the ballot mode and the
+// voter weight. It fails if there are more than limit ballots.
+func (c *Circuit) EnumerateBallots(mode Mode, weight uint64, limit int) ([][]uint64, error) {
+	s, err := c.newBallotSpace(mode, weight)
+	if err != nil {
+		return nil, err
+	}
+	if total := s.count(); total.Cmp(big.NewInt(int64(limit))) > 0 {
+		return nil, fmt.Errorf("too many ballots to enumerate: got %s, limit %d", total, limit)
+	}
+	var ballots [][]uint64
+	if s.mode.UniqueValues {
+		s.enumerateSets(0, s.mode.NumFields, 0, nil, func(values []uint64) {
+			permute(values, func(fields []uint64) { ballots = append(ballots, slices.Clone(fields)) })
+		})
+		slices.SortFunc(ballots, slices.Compare)
+	} else {
+		s.enumerateFields(0, 0, make([]uint64, 0, s.mode.NumFields), func(fields []uint64) {
+			ballots = append(ballots, slices.Clone(fields))
+		})
+	}
+	return ballots, nil
+}
+
+// SampleBallot returns a ballot accepted by the ballot mode in the default
+// circuit, as described in Circuit.SampleBallot.
+func SampleBallot(mode Mode, weight uint64, r io.Reader) ([]uint64, error) {
+	return defaultCircuit.SampleBallot(mode, weight, r)
+}
+
+// SampleBallot returns a vector of num_fields values chosen uniformly at
+// random among the ballots accepted by the ballot mode and the voter weight,
+// reading the randomness from r. If r is nil, crypto/rand is used.
+func (c *Circuit) SampleBallot(mode Mode, weight uint64, r io.Reader) ([]uint64, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	s, err := c.newBallotSpace(mode, weight)
+	if err != nil {
+		return nil, err
+	}
+	if s.count().Sign() == 0 {
+		return nil, fmt.Errorf("no ballot is accepted by the ballot mode")
+	}
+	if s.mode.UniqueValues {
+		return s.sampleSet(r)
+	}
+	return s.sampleFields(r)
+}
+
+// valueClass is a run of count consecutive field values starting at value
+// with the same cost, clamped to the bound of the ballot space.
+type valueClass struct {
+	value uint64
+	count uint64
+	cost  uint64
+}
+
+// ballotSpace holds the counts of the ballots of a mode by cost. Costs are
+// clamped to bound: with max_value_sum, bound is the cost limit plus one and
+// rejects the ballot, and otherwise it is min_value_sum and any larger cost
+// is equivalent.
+type ballotSpace struct {
+	mode    Mode
+	hasMax  bool
+	bound   uint64
+	classes []valueClass
+	// ways[i][cost] counts the accepted completions of the fields from i
+	// on, without unique_values.
+	ways [][]*big.Int
+	// sets[i][j][cost] counts the accepted sets of j more values taken from
+	// the classes from i on, with unique_values.
+	sets [][][]*big.Int
+}
+
+func (c *Circuit) newBallotSpace(mode Mode, weight uint64) (*ballotSpace, error) {
+	if err := c.ValidateMode(mode); err != nil {
+		return nil, err
+	}
+	s := &ballotSpace{mode: mode, hasMax: mode.MaxValueSum > 0, bound: mode.MinValueSum}
+	if s.hasMax {
+		limit := mode.MaxValueSum
+		if mode.CostFromWeight {
+			limit = weight
+		}
+		if limit >= maxCountStates-1 {
+			return nil, fmt.Errorf("ballot mode too large to count: cost limit %d, max %d", limit, maxCountStates-2)
+		}
+		s.bound = limit + 1
+	} else if s.bound >= maxCountStates {
+		return nil, fmt.Errorf("ballot mode too large to count: min_value_sum %d, max %d", s.bound, maxCountStates-1)
+	}
+	s.classes = s.valueClasses()
+	if states := uint64(len(s.classes)+1) * uint64(mode.NumFields+1) * (s.bound + 1); states > maxCountStates {
+		return nil, fmt.Errorf("ballot mode too large to count: %d states, max %d", states, maxCountStates)
+	}
+	if mode.UniqueValues {
+		s.countSets()
+	} else {
+		s.countFields()
+	}
+	return s, nil
+}
+
+// valueClasses splits the values from min_value to max_value into classes,
+// one per value while the cost is below the bound and a last one for the
+// values that reach it. With a cost exponent of 0 every value costs 1.
+func (s *ballotSpace) valueClasses() []valueClass {
+	mode := s.mode
+	if mode.CostExponent == 0 {
+		return []valueClass{{value: mode.MinValue, count: mode.MaxValue - mode.MinValue + 1, cost: min(1, s.bound)}}
+	}
+	var classes []valueClass
+	bound, exp := new(big.Int).SetUint64(s.bound), big.NewInt(int64(mode.CostExponent))
+	for v := mode.MinValue; v <= mode.MaxValue; v++ {
+		cost := new(big.Int).Exp(new(big.Int).SetUint64(v), exp, nil)
+		if cost.Cmp(bound) >= 0 {
+			return append(classes, valueClass{value: v, count: mode.MaxValue - v + 1, cost: s.bound})
+		}
+		classes = append(classes, valueClass{value: v, count: 1, cost: cost.Uint64()})
+	}
+	return classes
+}
+
+// next returns the cost after adding the cost of t values of the class.
+func (s *ballotSpace) next(cost uint64, class valueClass, t uint64) uint64 {
+	return min(cost+t*class.cost, s.bound)
+}
+
+// accepted reports whether a ballot of the cost passes the cost rules.
+func (s *ballotSpace) accepted(cost uint64) bool {
+	return cost >= s.mode.MinValueSum && (!s.hasMax || cost < s.bound)
+}
+
+// count returns the number of accepted ballots.
+func (s *ballotSpace) count() *big.Int {
+	if !s.mode.UniqueValues {
+		return new(big.Int).Set(s.ways[0][0])
+	}
+	// every set of distinct values is a ballot in each of its orders
+	total := new(big.Int).MulRange(1, int64(s.mode.NumFields))
+	return total.Mul(total, s.sets[0][s.mode.NumFields][0])
+}
+
+// countFields fills ways from the last field to the first.
+func (s *ballotSpace) countFields() {
+	n := s.mode.NumFields
+	s.ways = make([][]*big.Int, n+1)
+	for i := n; i >= 0; i-- {
+		s.ways[i] = make([]*big.Int, s.bound+1)
+		for cost := range s.ways[i] {
+			w := new(big.Int)
+			if i == n {
+				if s.accepted(uint64(cost)) {
+					w.SetInt64(1)
+				}
+			} else {
+				for _, class := range s.classes {
+					next := s.ways[i+1][s.next(uint64(cost), class, 1)]
+					w.Add(w, new(big.Int).Mul(new(big.Int).SetUint64(class.count), next))
+				}
+			}
+			s.ways[i][cost] = w
+		}
+	}
+}
+
+// countSets fills sets from the last class to the first.
+func (s *ballotSpace) countSets() {
+	n, k := s.mode.NumFields, len(s.classes)
+	s.sets = make([][][]*big.Int, k+1)
+	for i := k; i >= 0; i-- {
+		s.sets[i] = make([][]*big.Int, n+1)
+		for j := range s.sets[i] {
+			s.sets[i][j] = make([]*big.Int, s.bound+1)
+			for cost := range s.sets[i][j] {
+				w := new(big.Int)
+				if i == k {
+					if j == 0 && s.accepted(uint64(cost)) {
+						w.SetInt64(1)
+					}
+				} else {
+					class := s.classes[i]
+					for t := 0; t <= j && uint64(t) <= class.count; t++ {
+						next := s.sets[i+1][j-t][s.next(uint64(cost), class, uint64(t))]
+						w.Add(w, new(big.Int).Mul(binomial(class.count, t), next))
+					}
+				}
+				s.sets[i][j][cost] = w
+			}
+		}
+	}
+}
+
+// enumerateFields calls yield with every accepted completion of the fields,
+// in lexicographic order.
+func (s *ballotSpace) enumerateFields(i int, cost uint64, fields []uint64, yield func([]uint64)) {
+	if i == s.mode.NumFields {
+		yield(fields)
+		return
+	}
+	for _, class := range s.classes {
+		next := s.next(cost, class, 1)
+		if s.ways[i+1][next].Sign() == 0 {
+			continue
+		}
+		for v := range class.count {
+			s.enumerateFields(i+1, next, append(fields, class.value+v), yield)
+		}
+	}
+}
+
+// enumerateSets calls yield with every accepted set of j more distinct values
+// taken from the classes from i on.
+func (s *ballotSpace) enumerateSets(i, j int, cost uint64, values []uint64, yield func([]uint64)) {
+	if i == len(s.classes) {
+		yield(values)
+		return
+	}
+	class := s.classes[i]
+	for t := 0; t <= j && uint64(t) <= class.count; t++ {
+		next := s.next(cost, class, uint64(t))
+		if s.sets[i+1][j-t][next].Sign() == 0 {
+			continue
+		}
+		combinations(class.count, t, func(offsets []uint64) {
+			picked := slices.Clone(values)
+			for _, o := range offsets {
+				picked = append(picked, class.value+o)
+			}
+			s.enumerateSets(i+1, j-t, next, picked, yield)
+		})
+	}
+}
+
+// sampleFields draws the fields one by one, each value with probability
+// proportional to the accepted completions it leaves.
+func (s *ballotSpace) sampleFields(r io.Reader) ([]uint64, error) {
+	fields := make([]uint64, s.mode.NumFields)
+	cost := uint64(0)
+	for i := range fields {
+		x, err := rand.Int(r, s.ways[i][cost])
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample ballot: %w", err)
+		}
+		for _, class := range s.classes {
+			next := s.next(cost, class, 1)
+			ways := s.ways[i+1][next]
+			w := new(big.Int).Mul(new(big.Int).SetUint64(class.count), ways)
+			if x.Cmp(w) < 0 {
+				fields[i] = class.value + new(big.Int).Quo(x, ways).Uint64()
+				cost = next
+				break
+			}
+			x.Sub(x, w)
+		}
+	}
+	return fields, nil
+}
+
+// sampleSet draws a set of distinct values class by class and shuffles it,
+// as every set is accepted in all its orders.
+func (s *ballotSpace) sampleSet(r io.Reader) ([]uint64, error) {
+	values := make([]uint64, 0, s.mode.NumFields)
+	j, cost := s.mode.NumFields, uint64(0)
+	for i, class := range s.classes {
+		if j == 0 {
+			break
+		}
+		x, err := rand.Int(r, s.sets[i][j][cost])
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample ballot: %w", err)
+		}
+		for t := 0; t <= j && uint64(t) <= class.count; t++ {
+			next := s.next(cost, class, uint64(t))
+			w := new(big.Int).Mul(binomial(class.count, t), s.sets[i+1][j-t][next])
+			if x.Cmp(w) >= 0 {
+				x.Sub(x, w)
+				continue
+			}
+			offsets, err := sampleCombination(r, class.count, t)
+			if err != nil {
+				return nil, err
+			}
+			for _, o := range offsets {
+				values = append(values, class.value+o)
+			}
+			j, cost = j-t, next
+			break
+		}
+	}
+	// Fisher–Yates shuffle
+	for i := len(values) - 1; i > 0; i-- {
+		x, err := rand.Int(r, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample ballot: %w", err)
+		}
+		k := x.Int64()
+		values[i], values[k] = values[k], values[i]
+	}
+	return values, nil
+}
+
+// binomial returns n choose k.
+func binomial(n uint64, k int) *big.Int {
+	b := big.NewInt(1)
+	for i := range uint64(k) {
+		b.Mul(b, new(big.Int).SetUint64(n-i))
+		b.Quo(b, new(big.Int).SetUint64(i+1))
+	}
+	return b
+}
+
+// combinations calls yield with the k-subsets of 0 to n-1 in lexicographic
+// order.
+func combinations(n uint64, k int, yield func([]uint64)) {
+	offsets := make([]uint64, k)
+	var walk func(i int, from uint64)
+	walk = func(i int, from uint64) {
+		if i == k {
+			yield(offsets)
+			return
+		}
+		for o := from; o+uint64(k-i) <= n; o++ {
+			offsets[i] = o
+			walk(i+1, o+1)
+		}
+	}
+	walk(0, 0)
+}
+
+// sampleCombination returns a uniformly random k-subset of 0 to n-1, with
+// Floyd's algorithm.
+func sampleCombination(r io.Reader, n uint64, k int) ([]uint64, error) {
+	picked := make(map[uint64]bool, k)
+	offsets := make([]uint64, 0, k)
+	for j := n - uint64(k); j < n; j++ {
+		x, err := rand.Int(r, new(big.Int).SetUint64(j+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to sample ballot: %w", err)
+		}
+		o := x.Uint64()
+		if picked[o] {
+			o = j
+		}
+		picked[o] = true
+		offsets = append(offsets, o)
+	}
+	return offsets, nil
+}
+
+// permute calls yield with every order of the values, which are distinct.
+func permute(values []uint64, yield func([]uint64)) {
+	var walk func(i int)
+	walk = func(i int) {
+		if i == len(values) {
+			yield(values)
+			return
+		}
+		for j := i; j < len(values); j++ {
+			values[i], values[j] = values[j], values[i]
+			walk(i + 1)
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+	walk(0)
+}
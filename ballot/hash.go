@@ -0,0 +1,87 @@
+package ballot
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+)
+
+// MaxHashInputs is the maximum number of inputs of MultiHash, as supported
+// by circuits/lib/multiposeidon.circom.
+const MaxHashInputs = 256
+
+// hashChunkSize is the number of inputs hashed by each Poseidon instance of
+// MultiHash.
+const hashChunkSize = 16
+
+// PoseidonHash computes the circomlib Poseidon hash of the inputs. Like
+// circom does with the circuit inputs, they are reduced modulo the BN254
+// scalar field, so values such as 32-byte process IDs are accepted.
+func PoseidonHash(inputs ...*big.Int) (*big.Int, error) {
+	reduced := make([]*big.Int, len(inputs))
+	for i, in := range inputs {
+		if in == nil {
+			return nil, fmt.Errorf("missing hash input %d", i)
+		}
+		reduced[i] = new(big.Int).Mod(in, fr.Modulus())
+	}
+	return poseidon.Hash(reduced)
+}
+
+// MultiHash matches circuits/lib/multiposeidon.circom: up to 16 inputs are
+// hashed with a single Poseidon, otherwise the Poseidon hashes of chunks of
+// 16 inputs are hashed together.
+func MultiHash(inputs []*big.Int) (*big.Int, error) {
+	if len(inputs) == 0 || len(inputs) > MaxHashInputs {
+		return nil, fmt.Errorf("invalid number of hash inputs: got %d, want 1 to %d", len(inputs), MaxHashInputs)
+	}
+	if len(inputs) <= hashChunkSize {
+		return PoseidonHash(inputs...)
+	}
+	var chunkHashes []*big.Int
+	for i := 0; i < len(inputs); i += hashChunkSize {
+		end := min(i+hashChunkSize, len(inputs))
+		h, err := PoseidonHash(inputs[i:end]...)
+		if err != nil {
+			return nil, err
+		}
+		chunkHashes = append(chunkHashes, h)
+	}
+	return PoseidonHash(chunkHashes...)
+}
+
+// VoteID returns the vote ID of a ballot, the Poseidon hash of the process
+// ID, the voter address and the secret k truncated to 160 bits, as checked by
+// circuits/lib/vote_id.circom.
+func VoteID(processID, address, k *big.Int) (*big.Int, error) {
+	hash, err := PoseidonHash(processID, address, k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate vote ID: %w", err)
+	}
+	return TruncateTo160Bits(hash), nil
+}
+
+// TruncateTo160Bits returns the 160 least significant bits of input.
+func TruncateTo160Bits(input *big.Int) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), 160)
+	mask.Sub(mask, big.NewInt(1))
+	return new(big.Int).And(input, mask)
+}
+
+// DerivePoseidonChain derives n+1 values where out[0] = seed and
+// out[i+1] = Poseidon(out[i]). The ballot cipher encrypts field i with
+// out[i+1].
+func DerivePoseidonChain(seed *big.Int, n int) ([]*big.Int, error) {
+	out := make([]*big.Int, n+1)
+	out[0] = new(big.Int).Set(seed)
+	for i := 0; i < n; i++ {
+		h, err := PoseidonHash(out[i])
+		if err != nil {
+			return nil, err
+		}
+		out[i+1] = h
+	}
+	return out, nil
+}
@@ -0,0 +1,139 @@
+package ballot
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// maxFieldValue is the largest field value ElGamal can encrypt, as every
+// field goes through Num2Bits(msg_bits).
+const maxFieldValue = 1<<elgamal.MsgBits - 1
+
+// ModeError is a ballot mode setting that the circuit can never satisfy, or
+// that rejects ballots the mode is meant to accept.
+type ModeError struct {
+	Rule   Rule
+	Reason string
+}
+
+func (e *ModeError) Error() string {
+	return fmt.Sprintf("invalid ballot mode %s: %s", e.Rule, e.Reason)
+}
+
+// ModeErrors lists all the violations of a ballot mode.
+type ModeErrors []*ModeError
+
+func (e ModeErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateMode checks the ballot mode against the limits of the default
+// circuit, as described in Circuit.ValidateMode.
+func ValidateMode(mode Mode) error {
+	return defaultCircuit.ValidateMode(mode)
+}
+
+// ValidateMode checks the ballot mode against the limits of the circuit and
+// returns ModeErrors with every violation, or nil if the mode is usable:
+// num_fields must fit in MaskGenerator, values in the msg_bits of ElGamal
+// and costs in the comparators of BallotChecker, and some ballot must
+// satisfy all the bounds together.
+func (c *Circuit) ValidateMode(mode Mode) error {
+	var errs ModeErrors
+	add := func(rule Rule, format string, a ...any) {
+		errs = append(errs, &ModeError{Rule: rule, Reason: fmt.Sprintf(format, a...)})
+	}
+
+	switch {
+	case mode.NumFields < 0:
+		add(RuleNumFields, "negative number of fields %d", mode.NumFields)
+	case mode.NumFields > c.nFields:
+		add(RuleNumFields, "num_fields %d is greater than the %d circuit fields", mode.NumFields, c.nFields)
+	}
+	if mode.CostExponent < 0 {
+		add(RuleCostExponent, "negative exponent %d", mode.CostExponent)
+	}
+	if mode.MaxValue > maxFieldValue {
+		add(RuleMaxValue, "max_value %d does not fit in the %d-bit messages of ElGamal", mode.MaxValue, elgamal.MsgBits)
+	}
+	if mode.MinValue > maxFieldValue {
+		add(RuleMinValue, "min_value %d does not fit in the %d-bit messages of ElGamal", mode.MinValue, elgamal.MsgBits)
+	}
+	if mode.MinValue > mode.MaxValue {
+		add(RuleMinValue, "min_value %d is greater than max_value %d", mode.MinValue, mode.MaxValue)
+	}
+	// the cost is bounded by max_value_sum unless it is 0 or the bound is
+	// the weight
+	bounded := mode.MaxValueSum > 0 && !mode.CostFromWeight
+	if bounded && mode.MinValueSum > mode.MaxValueSum {
+		add(RuleMinValueSum, "min_value_sum %d is greater than max_value_sum %d", mode.MinValueSum, mode.MaxValueSum)
+	}
+	if len(errs) > 0 || mode.NumFields == 0 {
+		return errsOrNil(errs)
+	}
+
+	n := uint64(mode.NumFields)
+	if mode.UniqueValues && mode.MaxValue-mode.MinValue < n-1 {
+		add(RuleUniqueValues, "%d distinct values do not fit between %d and %d", n, mode.MinValue, mode.MaxValue)
+		return errs
+	}
+
+	// the cheapest and the most expensive ballots, with distinct values if
+	// unique_values is set
+	step := uint64(0)
+	if mode.UniqueValues {
+		step = 1
+	}
+	minCost, minOverflow := boundCost(mode.MinValue, step, n, mode.CostExponent)
+	maxCost, maxOverflow := boundCost(mode.MaxValue, -step, n, mode.CostExponent)
+	switch {
+	case minOverflow:
+		add(RuleCostExponent, "the cost of every ballot overflows the %d-bit comparators", comparatorBits)
+	case maxOverflow:
+		add(RuleCostExponent, "the cost of %d fields of value %d overflows the %d-bit comparators",
+			n, mode.MaxValue, comparatorBits)
+	}
+	if !maxOverflow && maxCost.Cmp(new(big.Int).SetUint64(mode.MinValueSum)) < 0 {
+		add(RuleMinValueSum, "min_value_sum %d is greater than the maximum cost %s", mode.MinValueSum, maxCost)
+	}
+	if bounded && !minOverflow && minCost.Cmp(new(big.Int).SetUint64(mode.MaxValueSum)) > 0 {
+		add(RuleMaxValueSum, "max_value_sum %d is less than the minimum cost %s", mode.MaxValueSum, minCost)
+	}
+	return errsOrNil(errs)
+}
+
+// boundCost returns the cost of n fields valued from, from+step, ... raised
+// to exp, or true in overflow if it does not fit in the comparators of
+// BallotChecker. A step of -1, wrapping around, walks down from the value.
+func boundCost(from, step, n uint64, exp int) (cost *big.Int, overflow bool) {
+	limit := new(big.Int).Lsh(big.NewInt(1), comparatorBits)
+	cost = new(big.Int)
+	e := big.NewInt(int64(exp))
+	for i := range n {
+		v := from + i*step
+		// values above 1 overflow with large exponents before computing them
+		if v > 1 && exp > comparatorBits {
+			return nil, true
+		}
+		cost.Add(cost, new(big.Int).Exp(new(big.Int).SetUint64(v), e, nil))
+		if cost.Cmp(limit) >= 0 {
+			return nil, true
+		}
+	}
+	return cost, false
+}
+
+// errsOrNil returns the violations as an error, or nil if there are none.
+func errsOrNil(errs ModeErrors) error {
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package nullifier
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+)
+
+// FileStore is a Store persisted to an append-only log with one JSON record
+// per accepted vote ID. The log is replayed into memory when opened, and
+// every record is synced to disk before Record returns.
+type FileStore struct {
+	mem  *MemoryStore
+	file *os.File
+	// size is the length of the complete records of the log
+	size int64
+	// err is set when a failed record could not be rolled back, and fails
+	// every later one
+	err error
+}
+
+// logRecord is a line of the FileStore log, with decimal values.
+type logRecord struct {
+	ProcessID string `json:"processId"`
+	Address   string `json:"address"`
+	VoteID    string `json:"voteId"`
+}
+
+// OpenFileStore opens the store logged at path, creating it if needed. A
+// last record without its newline, left by a write interrupted by a crash,
+// was never accepted and is truncated.
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vote ID log: %w", err)
+	}
+	s := &FileStore{mem: NewMemoryStore(), file: file}
+	if err := s.replay(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay loads the complete records of the log into memory and truncates an
+// incomplete last one.
+func (s *FileStore) replay() error {
+	reader := bufio.NewReader(s.file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(data) == 0 {
+				return nil
+			}
+			if err := s.file.Truncate(s.size); err != nil {
+				return fmt.Errorf("failed to truncate vote ID log: %w", err)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read vote ID log: %w", err)
+		}
+		if err := s.load(data); err != nil {
+			return fmt.Errorf("invalid vote ID log line %d: %w", line, err)
+		}
+		s.size += int64(len(data))
+	}
+}
+
+// load adds a record of the log to memory.
+func (s *FileStore) load(data []byte) error {
+	var r logRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+	values := make([]*big.Int, 3)
+	for i, v := range []string{r.ProcessID, r.Address, r.VoteID} {
+		var ok bool
+		if values[i], ok = new(big.Int).SetString(v, 10); !ok {
+			return fmt.Errorf("invalid value %q", v)
+		}
+	}
+	if err := validate(values[0], values[1], values[2]); err != nil {
+		return err
+	}
+	if err := s.mem.check(values[0], values[2]); err != nil {
+		return err
+	}
+	s.mem.add(values[0], values[1], values[2])
+	return nil
+}
+
+// Record implements Store. If the record cannot be written or synced, the
+// log is truncated back to its previous records, so that neither a fragment
+// nor an unaccepted record is replayed. If that fails too, the store refuses
+// any further records.
+func (s *FileStore) Record(processID, address, voteID *big.Int) (*Vote, error) {
+	if err := validate(processID, address, voteID); err != nil {
+		return nil, err
+	}
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	if err := s.mem.check(processID, voteID); err != nil {
+		return nil, err
+	}
+	line, err := json.Marshal(logRecord{ProcessID: processID.String(), Address: address.String(), VoteID: voteID.String()})
+	if err != nil {
+		return nil, err
+	}
+	line = append(line, '\n')
+	if _, err := s.file.Write(line); err != nil {
+		return nil, s.rollback(fmt.Errorf("failed to write vote ID log: %w", err))
+	}
+	if err := s.file.Sync(); err != nil {
+		return nil, s.rollback(fmt.Errorf("failed to sync vote ID log: %w", err))
+	}
+	s.size += int64(len(line))
+	return s.mem.add(processID, address, voteID), nil
+}
+
+// rollback truncates the log to its complete records after a failed record,
+// or marks the store as failed if it cannot, and returns the record error.
+func (s *FileStore) rollback(err error) error {
+	if terr := s.file.Truncate(s.size); terr != nil {
+		s.err = fmt.Errorf("%w, and failed to truncate it: %v", err, terr)
+		return s.err
+	}
+	return err
+}
+
+// Contains implements Store.
+func (s *FileStore) Contains(processID, voteID *big.Int) (bool, error) {
+	return s.mem.Contains(processID, voteID)
+}
+
+// Latest implements Store.
+func (s *FileStore) Latest(processID, address *big.Int) (*Vote, error) {
+	return s.mem.Latest(processID, address)
+}
+
+// Close closes the log.
+func (s *FileStore) Close() error {
+	return s.file.Close()
+}
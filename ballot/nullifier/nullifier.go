@@ -0,0 +1,162 @@
+// Package nullifier tracks the vote IDs accepted by the sequencer. The vote
+// ID of a ballot, ballot.VoteID(processID, address, k), is its nullifier: a
+// vote ID can only be accepted once per process, while a new vote of the same
+// address with another k overwrites the previous one.
+package nullifier
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// voteIDBits is the size of vote IDs, truncated to 160 bits.
+const voteIDBits = 160
+
+// ErrDuplicateVoteID is returned when recording a vote ID already accepted
+// in the process.
+var ErrDuplicateVoteID = errors.New("duplicated vote ID")
+
+// Vote is the vote ID accepted for an address in a process.
+type Vote struct {
+	VoteID *big.Int
+	// Previous is the vote ID overwritten by this vote, nil for the first
+	// vote of the address.
+	Previous *big.Int
+	// Overwrites is the number of times the address has voted again.
+	Overwrites int
+}
+
+// Store records the vote IDs accepted per process. Implementations are safe
+// for concurrent use, and Record is atomic: of two concurrent calls with the
+// same vote ID, exactly one succeeds.
+type Store interface {
+	// Record accepts the vote ID of an address in a process. It fails with
+	// ErrDuplicateVoteID if the vote ID was already accepted in the process.
+	// If the address voted before, the returned vote carries the overwritten
+	// vote ID, whose ballot must be removed from the tally.
+	Record(processID, address, voteID *big.Int) (*Vote, error)
+	// Contains reports whether the vote ID was accepted in the process.
+	Contains(processID, voteID *big.Int) (bool, error)
+	// Latest returns the last vote of an address in a process, or nil if it
+	// has not voted.
+	Latest(processID, address *big.Int) (*Vote, error)
+}
+
+// MemoryStore is an in-memory Store.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	processes map[string]*processVotes
+}
+
+// processVotes are the votes accepted in a process, by vote ID and by
+// address, with big integers keyed by their decimal string.
+type processVotes struct {
+	voteIDs map[string]string
+	latest  map[string]*Vote
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{processes: make(map[string]*processVotes)}
+}
+
+// Record implements Store.
+func (s *MemoryStore) Record(processID, address, voteID *big.Int) (*Vote, error) {
+	if err := validate(processID, address, voteID); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(processID, voteID); err != nil {
+		return nil, err
+	}
+	return s.add(processID, address, voteID), nil
+}
+
+// Contains implements Store.
+func (s *MemoryStore) Contains(processID, voteID *big.Int) (bool, error) {
+	if processID == nil || voteID == nil {
+		return false, fmt.Errorf("missing process ID or vote ID")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.processes[processID.String()]
+	if !ok {
+		return false, nil
+	}
+	_, ok = p.voteIDs[voteID.String()]
+	return ok, nil
+}
+
+// Latest implements Store.
+func (s *MemoryStore) Latest(processID, address *big.Int) (*Vote, error) {
+	if processID == nil || address == nil {
+		return nil, fmt.Errorf("missing process ID or address")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.processes[processID.String()]
+	if !ok {
+		return nil, nil
+	}
+	v, ok := p.latest[address.String()]
+	if !ok {
+		return nil, nil
+	}
+	return v.copy(), nil
+}
+
+// check fails if the vote ID was accepted in the process. The caller holds
+// the lock.
+func (s *MemoryStore) check(processID, voteID *big.Int) error {
+	p, ok := s.processes[processID.String()]
+	if !ok {
+		return nil
+	}
+	if _, ok := p.voteIDs[voteID.String()]; ok {
+		return fmt.Errorf("%w: %s in process %s", ErrDuplicateVoteID, voteID, processID)
+	}
+	return nil
+}
+
+// add records a vote ID checked with check. The caller holds the lock.
+func (s *MemoryStore) add(processID, address, voteID *big.Int) *Vote {
+	p, ok := s.processes[processID.String()]
+	if !ok {
+		p = &processVotes{voteIDs: make(map[string]string), latest: make(map[string]*Vote)}
+		s.processes[processID.String()] = p
+	}
+	vote := &Vote{VoteID: new(big.Int).Set(voteID)}
+	if prev, ok := p.latest[address.String()]; ok {
+		vote.Previous = prev.VoteID
+		vote.Overwrites = prev.Overwrites + 1
+	}
+	p.voteIDs[voteID.String()] = address.String()
+	p.latest[address.String()] = vote
+	return vote.copy()
+}
+
+// copy returns a copy of the vote that callers can modify.
+func (v *Vote) copy() *Vote {
+	c := &Vote{VoteID: new(big.Int).Set(v.VoteID), Overwrites: v.Overwrites}
+	if v.Previous != nil {
+		c.Previous = new(big.Int).Set(v.Previous)
+	}
+	return c
+}
+
+// validate checks the values recorded for a vote.
+func validate(processID, address, voteID *big.Int) error {
+	if processID == nil || address == nil || voteID == nil {
+		return fmt.Errorf("missing process ID, address or vote ID")
+	}
+	if processID.Sign() < 0 || address.Sign() < 0 || voteID.Sign() < 0 {
+		return fmt.Errorf("negative process ID, address or vote ID")
+	}
+	if voteID.BitLen() > voteIDBits {
+		return fmt.Errorf("invalid vote ID: more than %d bits", voteIDBits)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package ballot
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// System is a voting system that a ballot mode implements.
+type System string
+
+const (
+	SystemSingleChoice System = "single_choice"
+	SystemApproval     System = "approval"
+	SystemRankedChoice System = "ranked_choice"
+	SystemRating       System = "rating"
+	SystemQuadratic    System = "quadratic"
+	// SystemCustom is any other combination of the ballot mode rules.
+	SystemCustom System = "custom"
+)
+
+// The presets below return the ballot mode of common voting systems with
+// one field per candidate, so that the tally of each field is the result of
+// a candidate. The circuit must have at least as many fields as candidates.
+
+// SingleChoice returns the ballot mode choosing exactly one of the
+// candidates: each field is 0 or 1 and they add up to 1.
+func SingleChoice(candidates int) (Mode, error) {
+	if err := validateCandidates(candidates); err != nil {
+		return Mode{}, err
+	}
+	return validatePreset(Mode{NumFields: candidates, MaxValue: 1, MaxValueSum: 1, MinValueSum: 1, CostExponent: 1})
+}
+
+// Approval returns the ballot mode approving between minPicks and maxPicks
+// of the candidates: each field is 0 or 1 and they add up to a number of
+// picks within the bounds.
+func Approval(candidates, minPicks, maxPicks int) (Mode, error) {
+	if err := validateCandidates(candidates); err != nil {
+		return Mode{}, err
+	}
+	if minPicks < 0 || maxPicks < 1 || minPicks > maxPicks || maxPicks > candidates {
+		return Mode{}, fmt.Errorf("invalid number of picks: got %d to %d, want 0 <= min <= max <= %d candidates",
+			minPicks, maxPicks, candidates)
+	}
+	return validatePreset(Mode{
+		NumFields:    candidates,
+		MaxValue:     1,
+		MaxValueSum:  uint64(maxPicks),
+		MinValueSum:  uint64(minPicks),
+		CostExponent: 1,
+	})
+}
+
+// RankedChoice returns the ballot mode ranking all the candidates: the
+// fields are the distinct ranks 1 to candidates.
+func RankedChoice(candidates int) (Mode, error) {
+	if err := validateCandidates(candidates); err != nil {
+		return Mode{}, err
+	}
+	n := uint64(candidates)
+	return validatePreset(Mode{
+		NumFields:    candidates,
+		UniqueValues: true,
+		MaxValue:     n,
+		MinValue:     1,
+		MaxValueSum:  n * (n + 1) / 2,
+		MinValueSum:  n * (n + 1) / 2,
+		CostExponent: 1,
+	})
+}
+
+// Rating returns the ballot mode rating every candidate from minRating to
+// maxRating, with no bound on the total.
+func Rating(candidates int, minRating, maxRating uint64) (Mode, error) {
+	if err := validateCandidates(candidates); err != nil {
+		return Mode{}, err
+	}
+	if maxRating == 0 || minRating > maxRating {
+		return Mode{}, fmt.Errorf("invalid rating range: got %d to %d", minRating, maxRating)
+	}
+	return validatePreset(Mode{NumFields: candidates, MaxValue: maxRating, MinValue: minRating, CostExponent: 1})
+}
+
+// Quadratic returns the ballot mode of quadratic voting with the given
+// credits: casting v votes for a candidate costs v^2 credits.
+func Quadratic(candidates int, credits uint64) (Mode, error) {
+	if err := validateCandidates(candidates); err != nil {
+		return Mode{}, err
+	}
+	if credits == 0 {
+		return Mode{}, fmt.Errorf("invalid number of credits: got 0")
+	}
+	maxVotes := new(big.Int).Sqrt(new(big.Int).SetUint64(credits)).Uint64()
+	return validatePreset(Mode{NumFields: candidates, MaxValue: maxVotes, MaxValueSum: credits, CostExponent: 2})
+}
+
+// QuadraticWeighted returns the ballot mode of quadratic voting where the
+// credits of each voter are its weight, with at most maxVotes votes per
+// candidate.
+func QuadraticWeighted(candidates int, maxVotes uint64) (Mode, error) {
+	if err := validateCandidates(candidates); err != nil {
+		return Mode{}, err
+	}
+	if maxVotes == 0 {
+		return Mode{}, fmt.Errorf("invalid number of votes per candidate: got 0")
+	}
+	// max_value_sum only enables the bound, which is the weight
+	return validatePreset(Mode{NumFields: candidates, MaxValue: maxVotes, MaxValueSum: 1, CostExponent: 2, CostFromWeight: true})
+}
+
+// validateCandidates checks that the candidates fit in a ballot_proof
+// circuit.
+func validateCandidates(candidates int) error {
+	if candidates < 1 || candidates > MaxNFields {
+		return fmt.Errorf("invalid number of candidates: got %d, want 1 to %d", candidates, MaxNFields)
+	}
+	return nil
+}
+
+// validatePreset checks the mode of a preset with ValidateMode on the largest
+// circuit, as the presets do not know the circuit of the process.
+func validatePreset(mode Mode) (Mode, error) {
+	if err := (&Circuit{nFields: MaxNFields}).ValidateMode(mode); err != nil {
+		return Mode{}, err
+	}
+	return mode, nil
+}
+
+// Classify returns the voting system implemented by the ballot mode, or
+// SystemCustom if it matches none of the presets.
+func Classify(mode Mode) System {
+	n := uint64(max(mode.NumFields, 0))
+	switch {
+	case mode.NumFields < 1:
+		return SystemCustom
+	case mode.CostExponent == 2 && !mode.UniqueValues && mode.MinValue == 0 && mode.MinValueSum == 0 &&
+		mode.MaxValueSum > 0 && mode.MaxValue > 0:
+		return SystemQuadratic
+	case mode.CostExponent != 1 || mode.CostFromWeight:
+		return SystemCustom
+	case !mode.UniqueValues && mode.MinValue == 0 && mode.MaxValue == 1 &&
+		mode.MinValueSum == 1 && mode.MaxValueSum == 1:
+		return SystemSingleChoice
+	case !mode.UniqueValues && mode.MinValue == 0 && mode.MaxValue == 1 &&
+		mode.MinValueSum <= n && (mode.MaxValueSum == 0 || mode.MinValueSum <= mode.MaxValueSum):
+		return SystemApproval
+	case mode.UniqueValues && mode.MinValue == 1 && mode.MaxValue == n &&
+		mode.MinValueSum <= n*(n+1)/2 && (mode.MaxValueSum == 0 || mode.MaxValueSum >= n*(n+1)/2):
+		return SystemRankedChoice
+	case !mode.UniqueValues && mode.MaxValue > 0 && mode.MinValue <= mode.MaxValue &&
+		mode.MinValueSum <= n*mode.MinValue && (mode.MaxValueSum == 0 || mode.MaxValueSum >= n*mode.MaxValue):
+		return SystemRating
+	}
+	return SystemCustom
+}
+
+// Describe describes the ballot mode in human terms, naming its voting
+// system when it has one.
+func Describe(mode Mode) string {
+	n := mode.NumFields
+	switch Classify(mode) {
+	case SystemSingleChoice:
+		return fmt.Sprintf("single choice: choose one of %d candidates", n)
+	case SystemApproval:
+		maxPicks := uint64(n)
+		if mode.MaxValueSum > 0 {
+			maxPicks = min(mode.MaxValueSum, maxPicks)
+		}
+		if mode.MinValueSum == maxPicks {
+			return fmt.Sprintf("approval: approve exactly %d of %d candidates", maxPicks, n)
+		}
+		return fmt.Sprintf("approval: approve %d to %d of %d candidates", mode.MinValueSum, maxPicks, n)
+	case SystemRankedChoice:
+		return fmt.Sprintf("ranked choice: rank all %d candidates from 1 to %d", n, n)
+	case SystemRating:
+		return fmt.Sprintf("rating: rate each of %d candidates from %d to %d", n, mode.MinValue, mode.MaxValue)
+	case SystemQuadratic:
+		if mode.CostFromWeight {
+			return fmt.Sprintf("quadratic: spend the voter weight as credits on %d candidates, v votes cost v^2, at most %d votes each",
+				n, mode.MaxValue)
+		}
+		return fmt.Sprintf("quadratic: spend up to %d credits on %d candidates, v votes cost v^2, at most %d votes each",
+			mode.MaxValueSum, n, mode.MaxValue)
+	}
+	return describeRules(mode)
+}
+
+// describeRules describes the rules of a ballot mode one by one.
+func describeRules(mode Mode) string {
+	rules := []string{fmt.Sprintf("%d fields with values from %d to %d", mode.NumFields, mode.MinValue, mode.MaxValue)}
+	if mode.UniqueValues {
+		rules = append(rules, "all distinct")
+	}
+	cost := "sum of values"
+	if mode.CostExponent != 1 {
+		cost = fmt.Sprintf("sum of values^%d", mode.CostExponent)
+	}
+	switch {
+	case mode.MaxValueSum > 0 && mode.CostFromWeight:
+		rules = append(rules, fmt.Sprintf("%s at most the voter weight", cost))
+	case mode.MaxValueSum > 0:
+		rules = append(rules, fmt.Sprintf("%s at most %d", cost, mode.MaxValueSum))
+	}
+	if mode.MinValueSum > 0 {
+		rules = append(rules, fmt.Sprintf("%s at least %d", cost, mode.MinValueSum))
+	}
+	return "custom: " + strings.Join(rules, ", ")
+}
@@ -0,0 +1,46 @@
+package ballot
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// RerandomizeCipherfields re-encrypts each cipherfield of a ballot under the
+// encryption key (in TE coordinates) with fresh randomness read from rand,
+// returning the new cipherfields and a proof per field that they encrypt the
+// same votes. If rand is nil, crypto/rand is used.
+func RerandomizeCipherfields(cipherfields [][2][2]*big.Int, encryptionKey [2]*big.Int, rand io.Reader,
+) ([][2][2]*big.Int, []*elgamal.DLEQProof, error) {
+	out := make([][2][2]*big.Int, len(cipherfields))
+	proofs := make([]*elgamal.DLEQProof, len(cipherfields))
+	for i, cf := range cipherfields {
+		ct, proof, err := elgamal.NewCiphertext(cf[0], cf[1]).RerandomizeWithProof(encryptionKey[0], encryptionKey[1], rand)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to rerandomize cipherfield %d: %w", i, err)
+		}
+		out[i] = [2][2]*big.Int{ct.C1, ct.C2}
+		proofs[i] = proof
+	}
+	return out, proofs, nil
+}
+
+// VerifyRerandomizedCipherfields checks the proofs returned by
+// RerandomizeCipherfields, that out re-encrypts in field by field under the
+// encryption key.
+func VerifyRerandomizedCipherfields(encryptionKey [2]*big.Int, in, out [][2][2]*big.Int, proofs []*elgamal.DLEQProof) error {
+	if len(out) != len(in) || len(proofs) != len(in) {
+		return fmt.Errorf("invalid number of rerandomized cipherfields: got %d with %d proofs, want %d",
+			len(out), len(proofs), len(in))
+	}
+	for i := range in {
+		err := elgamal.VerifyRerandomization(encryptionKey[0], encryptionKey[1],
+			elgamal.NewCiphertext(in[i][0], in[i][1]), elgamal.NewCiphertext(out[i][0], out[i][1]), proofs[i])
+		if err != nil {
+			return fmt.Errorf("cipherfield %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package ballot
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// DecryptWithProofs decrypts the tally of each field as Decrypt does and
+// proves each result, so that anyone can check them against the accumulated
+// cipherfields with VerifyResults. If rand is nil, crypto/rand is used.
+func (a *Accumulator) DecryptWithProofs(priv *big.Int, table *elgamal.DLogTable, rand io.Reader,
+) ([]uint64, []*elgamal.DLEQProof, error) {
+	results := make([]uint64, len(a.fields))
+	proofs := make([]*elgamal.DLEQProof, len(a.fields))
+	for i, f := range a.fields {
+		var err error
+		if results[i], proofs[i], err = f.DecryptWithProof(priv, table, rand); err != nil {
+			return nil, nil, fmt.Errorf("failed to decrypt field %d: %w", i, err)
+		}
+	}
+	return results, proofs, nil
+}
+
+// VerifyResults checks published results against the accumulated
+// cipherfields of a tally, such as Accumulator.Cipherfields, the encryption
+// key (in TE coordinates) and the proofs returned by DecryptWithProofs.
+func VerifyResults(encryptionKey [2]*big.Int, cipherfields [][2][2]*big.Int, results []uint64, proofs []*elgamal.DLEQProof) error {
+	if len(results) != len(cipherfields) || len(proofs) != len(cipherfields) {
+		return fmt.Errorf("invalid number of results: got %d with %d proofs, want %d",
+			len(results), len(proofs), len(cipherfields))
+	}
+	for i, cf := range cipherfields {
+		err := elgamal.VerifyDecryption(encryptionKey[0], encryptionKey[1], elgamal.NewCiphertext(cf[0], cf[1]), results[i], proofs[i])
+		if err != nil {
+			return fmt.Errorf("field %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package ballot
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// SequencerBallotMode is the ballot mode of a process as served by the
+// DAVINCI sequencer, with boolean flags and decimal string bounds.
+type SequencerBallotMode struct {
+	NumFields      int    `json:"numFields"`
+	UniqueValues   bool   `json:"uniqueValues"`
+	MaxValue       string `json:"maxValue"`
+	MinValue       string `json:"minValue"`
+	MaxValueSum    string `json:"maxValueSum"`
+	MinValueSum    string `json:"minValueSum"`
+	CostExponent   int    `json:"costExponent"`
+	CostFromWeight bool   `json:"costFromWeight"`
+}
+
+// SequencerProcessData is the process data served by the DAVINCI sequencer.
+// The process ID and address are hex strings, with or without 0x prefix, and
+// the encryption key is given in RTE (gnark) coordinates.
+type SequencerProcessData struct {
+	ProcessID  string              `json:"processId"`
+	Address    string              `json:"address"`
+	PubKeyX    string              `json:"pubKeyX"`
+	PubKeyY    string              `json:"pubKeyY"`
+	BallotMode SequencerBallotMode `json:"ballotMode"`
+}
+
+// HexToDecimal converts a hex string, with or without 0x prefix, to a
+// decimal string.
+func HexToDecimal(hex string) (string, error) {
+	n, err := parseHex(hex)
+	if err != nil {
+		return "", err
+	}
+	return n.String(), nil
+}
+
+func parseHex(hex string) (*big.Int, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(hex, "0x"), "0X")
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok || s == "" || strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		return nil, fmt.Errorf("invalid hex string: %q", hex)
+	}
+	return n, nil
+}
+
+// ParseBallotMode converts a sequencer ballot mode to a Mode.
+func ParseBallotMode(m SequencerBallotMode) (Mode, error) {
+	mode := Mode{
+		NumFields:      m.NumFields,
+		UniqueValues:   m.UniqueValues,
+		CostExponent:   m.CostExponent,
+		CostFromWeight: m.CostFromWeight,
+	}
+	for _, v := range []struct {
+		name string
+		raw  string
+		dst  *uint64
+	}{
+		{"maxValue", m.MaxValue, &mode.MaxValue},
+		{"minValue", m.MinValue, &mode.MinValue},
+		{"maxValueSum", m.MaxValueSum, &mode.MaxValueSum},
+		{"minValueSum", m.MinValueSum, &mode.MinValueSum},
+	} {
+		n, err := strconv.ParseUint(v.raw, 10, 64)
+		if err != nil {
+			return Mode{}, fmt.Errorf("invalid ballot mode %s: %w", v.name, err)
+		}
+		*v.dst = n
+	}
+	return mode, nil
+}
+
+// EncryptionKey returns the process encryption key in TE (circom)
+// coordinates.
+func (d *SequencerProcessData) EncryptionKey() ([2]*big.Int, error) {
+	key, err := elgamal.ParsePointRTE(d.PubKeyX, d.PubKeyY)
+	if err != nil {
+		return [2]*big.Int{}, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return key.TE().Coordinates(), nil
+}
+
+// Ballot returns the ballot voting fields with the given weight and secret k
+// in the process, which must have as many fields as its ballot mode. If k is
+// nil, a random one is used when building the inputs.
+//
+// num_fields comes from the ballot mode, while the JS generateInputs takes it
+// from the number of fields; generateInputsFromSequencer makes the same check
+// so that both build the same inputs.
+func (d *SequencerProcessData) Ballot(fields []uint64, weight uint64, k *big.Int) (*Ballot, error) {
+	mode, err := ParseBallotMode(d.BallotMode)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != mode.NumFields {
+		return nil, fmt.Errorf("invalid number of fields: got %d, ballot mode has %d", len(fields), mode.NumFields)
+	}
+	processID, err := parseHex(d.ProcessID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid process ID: %w", err)
+	}
+	address, err := parseHex(d.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %w", err)
+	}
+	key, err := d.EncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Ballot{
+		Mode:          mode,
+		ProcessID:     processID,
+		Address:       address,
+		Weight:        weight,
+		EncryptionKey: key,
+		K:             k,
+		Fields:        fields,
+	}, nil
+}
+
+// BuildInputsFromSequencer builds the inputs of the default ballot_proof
+// circuit for the process data served by the sequencer, as the BallotBuilder
+// of the JS package does.
+func BuildInputsFromSequencer(d *SequencerProcessData, fields []uint64, weight uint64, k *big.Int) (*Inputs, error) {
+	return defaultCircuit.BuildInputsFromSequencer(d, fields, weight, k)
+}
+
+// BuildInputsFromSequencer builds the inputs of the circuit for the process
+// data served by the sequencer, as the BallotBuilder of the JS package does
+// with the same circuitCapacity.
+func (c *Circuit) BuildInputsFromSequencer(d *SequencerProcessData, fields []uint64, weight uint64, k *big.Int) (*Inputs, error) {
+	b, err := d.Ballot(fields, weight, k)
+	if err != nil {
+		return nil, err
+	}
+	return c.BuildInputs(b)
+}
@@ -0,0 +1,209 @@
+// Package testvectors generates reproducible ballot proof inputs. All the
+// randomness of a vector (encryption key, process ID, address and k) is read
+// from a stream derived from its seed, so named fixture sets can be
+// regenerated byte for byte and shared between the Go and JS tests.
+package testvectors
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	mathrand "math/rand/v2"
+	"os"
+	"path/filepath"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// Set describes a named fixture set: a ballot voting Fields with Weight
+// under Mode in a circuit with NFields fields, with randomness from Seed.
+type Set struct {
+	Name    string
+	Seed    string
+	NFields int
+	Mode    ballot.Mode
+	Fields  []uint64
+	Weight  uint64
+}
+
+// Sets are the fixture sets written to the artifacts directory.
+var Sets = []Set{
+	{
+		Name:    "rating",
+		Seed:    "davinci-circom/rating",
+		NFields: ballot.NFields,
+		Mode: ballot.Mode{
+			NumFields:    5,
+			UniqueValues: true,
+			MaxValue:     16,
+			MaxValueSum:  1125,
+			MinValueSum:  5,
+			CostExponent: 2,
+		},
+		Fields: []uint64{1, 2, 3, 4, 5},
+		Weight: 1,
+	},
+	{
+		Name:    "approval",
+		Seed:    "davinci-circom/approval",
+		NFields: ballot.NFields,
+		Mode: ballot.Mode{
+			NumFields:    6,
+			MaxValue:     1,
+			MaxValueSum:  3,
+			MinValueSum:  3,
+			CostExponent: 1,
+		},
+		Fields: []uint64{1, 0, 1, 0, 1, 0},
+		Weight: 1,
+	},
+	{
+		Name:    "quadratic_weighted",
+		Seed:    "davinci-circom/quadratic_weighted",
+		NFields: ballot.NFields,
+		Mode: ballot.Mode{
+			NumFields:      4,
+			MaxValue:       10,
+			MaxValueSum:    1,
+			CostExponent:   2,
+			CostFromWeight: true,
+		},
+		Fields: []uint64{5, 5, 7, 1},
+		Weight: 100,
+	},
+	{
+		Name:    "rating_16",
+		Seed:    "davinci-circom/rating_16",
+		NFields: 16,
+		Mode: ballot.Mode{
+			NumFields:    10,
+			UniqueValues: true,
+			MaxValue:     10,
+			MinValue:     1,
+			MinValueSum:  55,
+			CostExponent: 1,
+		},
+		Fields: []uint64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+		Weight: 1,
+	},
+}
+
+// Lookup returns the fixture set with the given name.
+func Lookup(name string) (Set, error) {
+	for _, s := range Sets {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return Set{}, fmt.Errorf("unknown fixture set: %s", name)
+}
+
+// NewReader returns a deterministic random stream derived from seed.
+func NewReader(seed string) io.Reader {
+	return mathrand.NewChaCha8(sha256.Sum256([]byte(seed)))
+}
+
+// Vector holds the inputs generated for a fixture set, with the private key
+// of the encryption key.
+type Vector struct {
+	Set        Set
+	PrivateKey *big.Int
+	Inputs     *ballot.Inputs
+}
+
+// Generate builds the vector of a fixture set, reading all its randomness
+// from the seed stream.
+func Generate(set Set) (*Vector, error) {
+	return GenerateFrom(set, NewReader(set.Seed))
+}
+
+// GenerateFrom builds the vector of a fixture set, reading all its randomness
+// from r in a fixed order: encryption key, process ID, address and k.
+func GenerateFrom(set Set, r io.Reader) (*Vector, error) {
+	circuit, err := ballot.NewCircuit(set.NFields)
+	if err != nil {
+		return nil, err
+	}
+	if err := circuit.CheckFields(set.Mode, set.Fields, set.Weight); err != nil {
+		return nil, fmt.Errorf("fixture set %s: %w", set.Name, err)
+	}
+	priv, pubX, pubY, err := elgamal.GenerateKey(r)
+	if err != nil {
+		return nil, err
+	}
+	processID, err := rand.Int(r, new(big.Int).Lsh(big.NewInt(1), 160))
+	if err != nil {
+		return nil, err
+	}
+	address, err := rand.Int(r, new(big.Int).Lsh(big.NewInt(1), 160))
+	if err != nil {
+		return nil, err
+	}
+	k, err := ballot.RandomK(r)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := circuit.BuildInputs(&ballot.Ballot{
+		Mode:          set.Mode,
+		ProcessID:     processID,
+		Address:       address,
+		Weight:        set.Weight,
+		EncryptionKey: [2]*big.Int{pubX, pubY},
+		K:             k,
+		Fields:        set.Fields,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fixture set %s: %w", set.Name, err)
+	}
+	return &Vector{Set: set, PrivateKey: priv, Inputs: inputs}, nil
+}
+
+// Expected returns the values the circuit is expected to compute and expose
+// for the vector.
+func (v *Vector) Expected() map[string]any {
+	return map[string]any{
+		"name":           v.Set.Name,
+		"seed":           v.Set.Seed,
+		"n_fields":       v.Set.NFields,
+		"private_key":    v.PrivateKey.String(),
+		"cipherfields":   ballot.StringifyCipherfields(v.Inputs.Cipherfields),
+		"vote_id":        v.Inputs.VoteID.String(),
+		"inputs_hash":    v.Inputs.InputsHash.String(),
+		"public_signals": v.Inputs.PublicSignals(),
+	}
+}
+
+// InputFile and ExpectedFile return the names of the files written for a
+// fixture set.
+func InputFile(name string) string    { return name + "_input.json" }
+func ExpectedFile(name string) string { return name + "_expected.json" }
+
+// Files returns the contents of the input and expected files of the vector.
+func (v *Vector) Files() (input, expected []byte, err error) {
+	if input, err = json.MarshalIndent(v.Inputs, "", "  "); err != nil {
+		return nil, nil, err
+	}
+	if expected, err = json.MarshalIndent(v.Expected(), "", "  "); err != nil {
+		return nil, nil, err
+	}
+	return append(input, '\n'), append(expected, '\n'), nil
+}
+
+// Write writes the input and expected files of the vector to dir.
+func (v *Vector) Write(dir string) error {
+	input, expected, err := v.Files()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, InputFile(v.Set.Name)), input, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ExpectedFile(v.Set.Name)), expected, 0o644)
+}
@@ -0,0 +1,164 @@
+package ballot
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/vocdoni/davinci-circom/circom2gnark"
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// ProofSignals are the public signals of a ballot proof by name, in the
+// order of circom2gnark.BallotProofSignals.
+type ProofSignals struct {
+	Address    *big.Int
+	VoteID     *big.Int
+	InputsHash *big.Int
+}
+
+// ParseProofSignals decodes the public signals of a ballot proof, as
+// returned by snarkjs.
+func ParseProofSignals(signals []string) (*ProofSignals, error) {
+	names := circom2gnark.BallotProofSignals.Names()
+	if len(signals) != len(names) {
+		return nil, fmt.Errorf("invalid number of ballot public signals: got %d, want %d", len(signals), len(names))
+	}
+	values := make(map[string]*big.Int, len(names))
+	for i, name := range names {
+		v, ok := new(big.Int).SetString(signals[i], 10)
+		if !ok || v.Sign() < 0 || v.Cmp(fr.Modulus()) >= 0 {
+			return nil, fmt.Errorf("invalid public signal %s: %q", name, signals[i])
+		}
+		values[name] = v
+	}
+	return &ProofSignals{
+		Address:    values["address"],
+		VoteID:     values["vote_id"],
+		InputsHash: values["inputs_hash"],
+	}, nil
+}
+
+// Strings encodes the signals in public signals order: address, vote_id and
+// inputs_hash.
+func (s *ProofSignals) Strings() []string {
+	return []string{s.Address.String(), s.VoteID.String(), s.InputsHash.String()}
+}
+
+// Submission is a ballot as submitted to the sequencer: the proof and its
+// public signals in snarkjs format, with all the values hashed into
+// inputs_hash.
+type Submission struct {
+	Proof         string
+	PublicSignals []string
+	ProcessID     *big.Int
+	Mode          Mode
+	EncryptionKey [2]*big.Int
+	Address       *big.Int
+	Weight        uint64
+	VoteID        *big.Int
+	Cipherfields  [][2][2]*big.Int
+}
+
+// Submission returns the submission of a ballot built from the inputs with
+// its proof and public signals.
+func (in *Inputs) Submission(proof string, publicSignals []string) *Submission {
+	return &Submission{
+		Proof:         proof,
+		PublicSignals: publicSignals,
+		ProcessID:     in.ProcessID,
+		Mode:          in.Mode,
+		EncryptionKey: in.EncryptionKey,
+		Address:       in.Address,
+		Weight:        in.Weight,
+		VoteID:        in.VoteID,
+		Cipherfields:  in.Cipherfields,
+	}
+}
+
+// VerifyBallot verifies a ballot submitted for the default ballot_proof
+// circuit against its verification key.
+func VerifyBallot(vkey []byte, s *Submission) error {
+	return defaultCircuit.VerifyBallot(vkey, s)
+}
+
+// VerifyBallot verifies a ballot submitted for the circuit: it recomputes
+// inputs_hash from the submitted values in the order of ballot_proof.circom,
+// checks it together with the address and the vote ID against the public
+// signals, and verifies the proof with the verification key.
+func (c *Circuit) VerifyBallot(vkey []byte, s *Submission) error {
+	if err := c.validateSubmission(s); err != nil {
+		return err
+	}
+	signals, err := ParseProofSignals(s.PublicSignals)
+	if err != nil {
+		return err
+	}
+	inputs := &Inputs{
+		Mode:          s.Mode,
+		ProcessID:     s.ProcessID,
+		Address:       s.Address,
+		Weight:        s.Weight,
+		EncryptionKey: s.EncryptionKey,
+		Cipherfields:  s.Cipherfields,
+		VoteID:        s.VoteID,
+	}
+	hash, err := inputs.ComputeInputsHash()
+	if err != nil {
+		return err
+	}
+	for _, v := range []struct {
+		name      string
+		got, want *big.Int
+	}{
+		{"address", signals.Address, s.Address},
+		{"vote_id", signals.VoteID, s.VoteID},
+		{"inputs_hash", signals.InputsHash, hash},
+	} {
+		if v.got.Cmp(new(big.Int).Mod(v.want, fr.Modulus())) != 0 {
+			return fmt.Errorf("public signal %s does not match the ballot: got %s, want %s", v.name, v.got, v.want)
+		}
+	}
+	ok, err := circom2gnark.VerifyCircomProof(vkey, s.Proof, s.PublicSignals)
+	if err != nil {
+		return fmt.Errorf("invalid ballot proof: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("invalid ballot proof")
+	}
+	return nil
+}
+
+// validateSubmission checks the values of a submission before hashing them.
+func (c *Circuit) validateSubmission(s *Submission) error {
+	if s.ProcessID == nil || s.Address == nil || s.VoteID == nil {
+		return fmt.Errorf("missing process ID, address or vote ID")
+	}
+	if s.ProcessID.Sign() < 0 || s.Address.Sign() < 0 || s.VoteID.Sign() < 0 {
+		return fmt.Errorf("negative process ID, address or vote ID")
+	}
+	if s.VoteID.BitLen() > 160 {
+		return fmt.Errorf("invalid vote ID: more than 160 bits")
+	}
+	if s.Mode.NumFields < 0 || s.Mode.NumFields > c.nFields {
+		return fmt.Errorf("invalid number of fields in ballot mode: got %d, max %d", s.Mode.NumFields, c.nFields)
+	}
+	if err := elgamal.ValidatePublicKey(s.EncryptionKey[0], s.EncryptionKey[1]); err != nil {
+		return fmt.Errorf("invalid encryption key: %w", err)
+	}
+	if len(s.Cipherfields) != c.nFields {
+		return fmt.Errorf("invalid number of cipherfields: got %d, want %d", len(s.Cipherfields), c.nFields)
+	}
+	for i, cf := range s.Cipherfields {
+		for j, p := range cf {
+			if p[0] == nil || p[1] == nil {
+				return fmt.Errorf("missing cipherfield %d", i)
+			}
+			if !(&elgamal.PointTE{X: p[0], Y: p[1]}).IsOnCurve() {
+				return fmt.Errorf("invalid cipherfield %d: C%d is not on the BabyJubJub curve", i, j+1)
+			}
+		}
+	}
+	return nil
+}
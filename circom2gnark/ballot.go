@@ -0,0 +1,135 @@
+package circom2gnark
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+	recursion "github.com/consensys/gnark/std/recursion/groth16"
+)
+
+// CircomSignals describes the public signals of a Circom circuit. SnarkJS
+// lists the outputs of the main component first, followed by its public
+// inputs in declaration order, whatever the order of the {public [...]}
+// list.
+type CircomSignals struct {
+	Outputs      []string
+	PublicInputs []string
+}
+
+// Names returns the signal names in public signals order.
+func (s CircomSignals) Names() []string {
+	return append(append([]string{}, s.Outputs...), s.PublicInputs...)
+}
+
+// Len returns the number of public signals.
+func (s CircomSignals) Len() int {
+	return len(s.Outputs) + len(s.PublicInputs)
+}
+
+// Index returns the position of the named signal in the public signals.
+func (s CircomSignals) Index(name string) (int, error) {
+	for i, n := range s.Names() {
+		if n == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("unknown public signal: %s", name)
+}
+
+// BallotProofSignals describes the public signals of ballot_proof.circom,
+// where address and vote_id are declared before inputs_hash.
+var BallotProofSignals = CircomSignals{
+	PublicInputs: []string{"address", "vote_id", "inputs_hash"},
+}
+
+// BallotPublicInputs holds the public signals of a ballot proof by name. It
+// is used both as an in-circuit public witness and as its assignment.
+type BallotPublicInputs struct {
+	Address    emulated.Element[sw_bn254.ScalarField]
+	VoteID     emulated.Element[sw_bn254.ScalarField]
+	InputsHash emulated.Element[sw_bn254.ScalarField]
+}
+
+// signals maps the BallotProofSignals names to the fields.
+func (b *BallotPublicInputs) signals() map[string]*emulated.Element[sw_bn254.ScalarField] {
+	return map[string]*emulated.Element[sw_bn254.ScalarField]{
+		"address":     &b.Address,
+		"vote_id":     &b.VoteID,
+		"inputs_hash": &b.InputsHash,
+	}
+}
+
+// Witness returns the public inputs in the order expected by the Groth16
+// verifier of the ballot proof.
+func (b *BallotPublicInputs) Witness() recursion.Witness[sw_bn254.ScalarField] {
+	fields := b.signals()
+	names := BallotProofSignals.Names()
+	public := make([]emulated.Element[sw_bn254.ScalarField], len(names))
+	for i, name := range names {
+		public[i] = *fields[name]
+	}
+	return recursion.Witness[sw_bn254.ScalarField]{Public: public}
+}
+
+// BallotPublicInputsFromWitness names the public inputs of a recursion
+// witness, such as GnarkRecursionProofBN254.PublicInputs.
+func BallotPublicInputsFromWitness(witness recursion.Witness[sw_bn254.ScalarField]) (*BallotPublicInputs, error) {
+	names := BallotProofSignals.Names()
+	if len(witness.Public) != len(names) {
+		return nil, fmt.Errorf("invalid number of ballot public inputs: got %d, want %d", len(witness.Public), len(names))
+	}
+	inputs := &BallotPublicInputs{}
+	fields := inputs.signals()
+	for i, name := range names {
+		*fields[name] = witness.Public[i]
+	}
+	return inputs, nil
+}
+
+// BallotRecursionProofBN254 carries a ballot proof formatted for recursion,
+// with its public inputs by name.
+type BallotRecursionProofBN254 struct {
+	Proof        recursion.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine]
+	Vk           recursion.VerifyingKey[sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl]
+	PublicInputs BallotPublicInputs
+}
+
+// Circom2BallotProofForRecursionBN254 converts a ballot proof and its public
+// signals into a recursion proof with named public inputs.
+func Circom2BallotProofForRecursionBN254(vkey []byte, rawCircomProof, rawPubSignals string, fixedVk bool) (*BallotRecursionProofBN254, error) {
+	recursionProof, err := Circom2GnarkProofForRecursionBN254WithVK(vkey, rawCircomProof, rawPubSignals, fixedVk)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := BallotPublicInputsFromWitness(recursionProof.PublicInputs)
+	if err != nil {
+		return nil, err
+	}
+	return &BallotRecursionProofBN254{
+		Proof:        recursionProof.Proof,
+		Vk:           recursionProof.Vk,
+		PublicInputs: *inputs,
+	}, nil
+}
+
+// Circom2BallotPlaceholderBN254 creates placeholders for circuits verifying
+// ballot proofs.
+func Circom2BallotPlaceholderBN254(vkey []byte, fixedVk bool) (*GnarkRecursionPlaceholdersBN254, error) {
+	return Circom2GnarkPlaceholderBN254WithVK(vkey, BallotProofSignals.Len(), fixedVk)
+}
+
+// AssertBallotProofBN254 verifies a ballot proof inside a BN254 circuit
+// against its named public inputs.
+func AssertBallotProofBN254(api frontend.API,
+	vk recursion.VerifyingKey[sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl],
+	proof recursion.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine],
+	inputs BallotPublicInputs, opts ...recursion.VerifierOption,
+) error {
+	verifier, err := recursion.NewVerifier[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](api)
+	if err != nil {
+		return err
+	}
+	return verifier.AssertProof(vk, proof, inputs.Witness(), opts...)
+}
@@ -0,0 +1,86 @@
+package circom2gnark
+
+import (
+	"fmt"
+
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+)
+
+const (
+	// CircomProofCompressedSizeBN254 is the size of a compressed BN254 proof:
+	// A (32 bytes) | B (64 bytes) | C (32 bytes).
+	CircomProofCompressedSizeBN254 = bn254.SizeOfG1AffineCompressed*2 + bn254.SizeOfG2AffineCompressed
+	// CircomProofUncompressedSizeBN254 is the size of an uncompressed BN254
+	// proof: A (64 bytes) | B (128 bytes) | C (64 bytes), with every
+	// coordinate as a 32-byte big-endian word in EIP-197 order.
+	CircomProofUncompressedSizeBN254 = bn254.SizeOfG1AffineUncompressed*2 + bn254.SizeOfG2AffineUncompressed
+)
+
+// MarshalBinaryBN254 encodes a Circom proof (BN254) into a fixed-size binary
+// form. Compressed proofs use gnark-crypto point compression and take
+// CircomProofCompressedSizeBN254 bytes. Uncompressed proofs take
+// CircomProofUncompressedSizeBN254 bytes and match the layout of the proof
+// argument of the Solidity verifier (A.x, A.y, B.x.a1, B.x.a0, B.y.a1,
+// B.y.a0, C.x, C.y).
+func (circomProof *CircomProof) MarshalBinaryBN254(compressed bool) ([]byte, error) {
+	proof, err := circomProof.ToGnarkBN254()
+	if err != nil {
+		return nil, err
+	}
+	if compressed {
+		out := make([]byte, 0, CircomProofCompressedSizeBN254)
+		a, b, c := proof.Ar.Bytes(), proof.Bs.Bytes(), proof.Krs.Bytes()
+		out = append(out, a[:]...)
+		out = append(out, b[:]...)
+		return append(out, c[:]...), nil
+	}
+	out := make([]byte, 0, CircomProofUncompressedSizeBN254)
+	a, b, c := proof.Ar.RawBytes(), proof.Bs.RawBytes(), proof.Krs.RawBytes()
+	out = append(out, a[:]...)
+	out = append(out, b[:]...)
+	return append(out, c[:]...), nil
+}
+
+// UnmarshalCircomProofBinaryBN254 decodes a proof encoded by
+// MarshalBinaryBN254. The encoding (compressed or not) is detected from the
+// input length. Every point is checked to be on the curve and in the correct
+// subgroup.
+func UnmarshalCircomProofBinaryBN254(data []byte) (*CircomProof, error) {
+	var g1Size, g2Size int
+	switch len(data) {
+	case CircomProofCompressedSizeBN254:
+		g1Size, g2Size = bn254.SizeOfG1AffineCompressed, bn254.SizeOfG2AffineCompressed
+	case CircomProofUncompressedSizeBN254:
+		g1Size, g2Size = bn254.SizeOfG1AffineUncompressed, bn254.SizeOfG2AffineUncompressed
+	default:
+		return nil, fmt.Errorf("invalid binary proof size: got %d, want %d or %d",
+			len(data), CircomProofCompressedSizeBN254, CircomProofUncompressedSizeBN254)
+	}
+	proof := &groth16_bn254.Proof{}
+	if _, err := proof.Ar.SetBytes(data[:g1Size]); err != nil {
+		return nil, fmt.Errorf("failed to decode PiA: %v", err)
+	}
+	if _, err := proof.Bs.SetBytes(data[g1Size : g1Size+g2Size]); err != nil {
+		return nil, fmt.Errorf("failed to decode PiB: %v", err)
+	}
+	if _, err := proof.Krs.SetBytes(data[g1Size+g2Size:]); err != nil {
+		return nil, fmt.Errorf("failed to decode PiC: %v", err)
+	}
+	return circomProofFromGnarkBN254(proof), nil
+}
+
+// circomProofFromGnarkBN254 formats a gnark BN254 proof as SnarkJS does,
+// using decimal projective coordinates with Z = 1.
+func circomProofFromGnarkBN254(proof *groth16_bn254.Proof) *CircomProof {
+	return &CircomProof{
+		PiA: []string{proof.Ar.X.String(), proof.Ar.Y.String(), "1"},
+		PiB: [][]string{
+			{proof.Bs.X.A0.String(), proof.Bs.X.A1.String()},
+			{proof.Bs.Y.A0.String(), proof.Bs.Y.A1.String()},
+			{"1", "0"},
+		},
+		PiC:      []string{proof.Krs.X.String(), proof.Krs.Y.String(), "1"},
+		Protocol: "groth16",
+	}
+}
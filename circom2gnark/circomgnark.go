@@ -2,11 +2,19 @@
 // proof formats, allowing for verification of zkSNARK proofs created with
 // Circom and SnarkJS to be used within the Gnark framework.
 // It includes functions to convert Circom proofs and verification keys to
-// Gnark over the BN254 curve, and to verify these proofs using Gnark's
+// Gnark over the BN254 and BLS12-381 curves, and to verify these proofs using Gnark's
 // verification functions. It also provides a way to handle recursive proofs
 // and placeholders for recursive circuits.
 package circom2gnark
 
+import (
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/solidity"
+)
+
 // Circom2GnarkProofForRecursionBN254 converts a Circom BN254 proof into a Gnark recursion proof with fixed VK.
 func Circom2GnarkProofForRecursionBN254(vkey []byte, rawCircomProof, rawPubSignals string) (*GnarkRecursionProofBN254, error) {
 	return Circom2GnarkProofForRecursionBN254WithVK(vkey, rawCircomProof, rawPubSignals, true)
@@ -55,4 +63,91 @@ func VerifyCircomProofBN254(vkey []byte, rawProof string, pubSignals []string) (
 		return false, err
 	}
 	return gnarkProof.Verify()
-}
\ No newline at end of file
+}
+
+// Circom2GnarkProofForRecursionBLS12381 converts a Circom BLS12-381 proof into a Gnark recursion proof,
+// allowing the caller to decide whether the verifying key is fixed in-circuit.
+func Circom2GnarkProofForRecursionBLS12381(vkey []byte, rawCircomProof, rawPubSignals string, fixedVk bool) (*GnarkRecursionProofBLS12381, error) {
+	circomProof, circomPubSignals, err := UnmarshalCircom(rawCircomProof, rawPubSignals)
+	if err != nil {
+		return nil, err
+	}
+	circomVerificationKey, err := UnmarshalCircomVerificationKeyJSON(vkey)
+	if err != nil {
+		return nil, err
+	}
+	return circomProof.ToGnarkRecursionBLS12381(circomVerificationKey, circomPubSignals, fixedVk)
+}
+
+// Circom2GnarkPlaceholderBLS12381 creates placeholders for BLS12-381 recursion circuits and lets caller choose fixed VK.
+func Circom2GnarkPlaceholderBLS12381(vkey []byte, nInputs int, fixedVk bool) (*GnarkRecursionPlaceholdersBLS12381, error) {
+	gnarkVKeyData, err := UnmarshalCircomVerificationKeyJSON(vkey)
+	if err != nil {
+		return nil, err
+	}
+	return PlaceholdersForRecursionBLS12381(gnarkVKeyData, nInputs, fixedVk)
+}
+
+// VerifyCircomProofBLS12381 verifies a Circom BLS12-381 proof natively using gnark-crypto.
+func VerifyCircomProofBLS12381(vkey []byte, rawProof string, pubSignals []string) (bool, error) {
+	circomProof, circomPubSignals, err := UnmarshalCircom(rawProof, stringMustJSON(pubSignals))
+	if err != nil {
+		return false, err
+	}
+	circomVerificationKey, err := UnmarshalCircomVerificationKeyJSON(vkey)
+	if err != nil {
+		return false, err
+	}
+	gnarkProof, err := circomProof.ToGnarkProofBLS12381(circomVerificationKey, circomPubSignals)
+	if err != nil {
+		return false, err
+	}
+	return gnarkProof.Verify()
+}
+
+// VerifyCircomProof verifies a SnarkJS proof natively, selecting the proving
+// system from the protocol field of the verification key (groth16, plonk or
+// fflonk) and, for Groth16, the curve from its curve field.
+func VerifyCircomProof(vkey []byte, rawProof string, pubSignals []string) (bool, error) {
+	circomVerificationKey, err := UnmarshalCircomVerificationKeyJSON(vkey)
+	if err != nil {
+		return false, err
+	}
+	switch circomVerificationKey.Protocol {
+	case "", ProtocolGroth16:
+	case ProtocolPlonk:
+		return VerifyCircomPlonkProofBN254(vkey, rawProof, pubSignals)
+	case ProtocolFflonk:
+		return VerifyCircomFflonkProofBN254(vkey, rawProof, pubSignals)
+	default:
+		return false, fmt.Errorf("unsupported protocol: %s", circomVerificationKey.Protocol)
+	}
+	curve, err := circomVerificationKey.CurveID()
+	if err != nil {
+		return false, err
+	}
+	switch curve {
+	case ecc.BLS12_381:
+		return VerifyCircomProofBLS12381(vkey, rawProof, pubSignals)
+	default:
+		return VerifyCircomProofBN254(vkey, rawProof, pubSignals)
+	}
+}
+
+// Circom2SolidityVerifierBN254 writes a Solidity Groth16 verifier contract for a Circom BN254 verification key.
+func Circom2SolidityVerifierBN254(vkey []byte, w io.Writer, opts ...solidity.ExportOption) error {
+	circomVerificationKey, err := UnmarshalCircomVerificationKeyJSON(vkey)
+	if err != nil {
+		return err
+	}
+	return circomVerificationKey.ExportSolidityBN254(w, opts...)
+}
+
+// Circom2SolidityCalldataBN254 converts a Circom BN254 proof and its public signals into verifyProof arguments.
+func Circom2SolidityCalldataBN254(rawCircomProof, rawPubSignals string) (*SolidityCalldataBN254, error) {
+	circomProof, circomPubSignals, err := UnmarshalCircom(rawCircomProof, rawPubSignals)
+	if err != nil {
+		return nil, err
+	}
+	return circomProof.ToSolidityCalldataBN254(circomPubSignals)
+}
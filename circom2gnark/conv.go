@@ -2,12 +2,39 @@ package circom2gnark
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls12381fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
 	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
 	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
 )
 
+// CurveID returns the curve declared by the verification key. Keys without a
+// curve field are assumed to be over BN254.
+func (circomVerificationKey *CircomVerificationKey) CurveID() (ecc.ID, error) {
+	switch strings.ToLower(circomVerificationKey.Curve) {
+	case "", CurveBN254, "bn254":
+		return ecc.BN254, nil
+	case CurveBLS12381, "bls12-381":
+		return ecc.BLS12_381, nil
+	default:
+		return ecc.UNKNOWN, fmt.Errorf("unsupported curve: %s", circomVerificationKey.Curve)
+	}
+}
+
+// checkProtocol rejects verification keys of proving systems other than
+// Groth16. Keys without a protocol field are assumed to be Groth16.
+func (circomVerificationKey *CircomVerificationKey) checkProtocol() error {
+	if p := circomVerificationKey.Protocol; p != "" && p != ProtocolGroth16 {
+		return fmt.Errorf("unsupported protocol: %s, want %s", p, ProtocolGroth16)
+	}
+	return nil
+}
+
 // ConvertPublicInputsBN254 parses public inputs into BN254 field elements.
 func ConvertPublicInputsBN254(publicSignals []string) ([]bn254fr.Element, error) {
 	publicInputs := make([]bn254fr.Element, len(publicSignals))
@@ -44,6 +71,9 @@ func (circomProof *CircomProof) ToGnarkBN254() (*groth16_bn254.Proof, error) {
 
 // ToGnarkBN254 converts a CircomVerificationKey into a Gnark-compatible verification key over BN254.
 func (circomVerificationKey *CircomVerificationKey) ToGnarkBN254() (*groth16_bn254.VerifyingKey, error) {
+	if err := circomVerificationKey.checkProtocol(); err != nil {
+		return nil, err
+	}
 	alphaG1, err := stringToG1BN254(circomVerificationKey.VkAlpha1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert VkAlpha1: %v", err)
@@ -91,4 +121,92 @@ func (proof *GnarkProofBN254) Verify() (bool, error) {
 		return false, fmt.Errorf("proof verification failed: %v", err)
 	}
 	return true, nil
-}
\ No newline at end of file
+}
+
+// ConvertPublicInputsBLS12381 parses public inputs into BLS12-381 field elements.
+func ConvertPublicInputsBLS12381(publicSignals []string) ([]bls12381fr.Element, error) {
+	publicInputs := make([]bls12381fr.Element, len(publicSignals))
+	for i, s := range publicSignals {
+		bi, err := stringToBigInt(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public input %d: %v", i, err)
+		}
+		publicInputs[i].SetBigInt(bi)
+	}
+	return publicInputs, nil
+}
+
+// ToGnarkBLS12381 converts a CircomProof into a Gnark-compatible Proof structure over BLS12-381.
+func (circomProof *CircomProof) ToGnarkBLS12381() (*groth16_bls12381.Proof, error) {
+	arG1, err := stringToG1BLS12381(circomProof.PiA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert PiA: %v", err)
+	}
+	krsG1, err := stringToG1BLS12381(circomProof.PiC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert PiC: %v", err)
+	}
+	bsG2, err := stringToG2BLS12381(circomProof.PiB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert PiB: %v", err)
+	}
+	return &groth16_bls12381.Proof{
+		Ar:  *arG1,
+		Krs: *krsG1,
+		Bs:  *bsG2,
+	}, nil
+}
+
+// ToGnarkBLS12381 converts a CircomVerificationKey into a Gnark-compatible verification key over BLS12-381.
+func (circomVerificationKey *CircomVerificationKey) ToGnarkBLS12381() (*groth16_bls12381.VerifyingKey, error) {
+	if err := circomVerificationKey.checkProtocol(); err != nil {
+		return nil, err
+	}
+	alphaG1, err := stringToG1BLS12381(circomVerificationKey.VkAlpha1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert VkAlpha1: %v", err)
+	}
+	betaG2, err := stringToG2BLS12381(circomVerificationKey.VkBeta2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert VkBeta2: %v", err)
+	}
+	gammaG2, err := stringToG2BLS12381(circomVerificationKey.VkGamma2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert VkGamma2: %v", err)
+	}
+	deltaG2, err := stringToG2BLS12381(circomVerificationKey.VkDelta2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert VkDelta2: %v", err)
+	}
+
+	numIC := len(circomVerificationKey.IC)
+	G1K := make([]bls12381.G1Affine, numIC)
+	for i, icPoint := range circomVerificationKey.IC {
+		icG1, err := stringToG1BLS12381(icPoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert IC[%d]: %v", i, err)
+		}
+		G1K[i] = *icG1
+	}
+
+	vk := &groth16_bls12381.VerifyingKey{}
+	vk.G1.Alpha = *alphaG1
+	vk.G1.K = G1K
+	vk.G2.Beta = *betaG2
+	vk.G2.Gamma = *gammaG2
+	vk.G2.Delta = *deltaG2
+
+	if err := vk.Precompute(); err != nil {
+		return nil, fmt.Errorf("failed to precompute verification key: %v", err)
+	}
+	return vk, nil
+}
+
+// Verify verifies the Gnark proof using the provided verification key and public inputs over BLS12-381.
+func (proof *GnarkProofBLS12381) Verify() (bool, error) {
+	err := groth16_bls12381.Verify(proof.Proof, proof.VerifyingKey, proof.PublicInputs)
+	if err != nil {
+		return false, fmt.Errorf("proof verification failed: %v", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,514 @@
+package circom2gnark
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// CircomFflonkProof represents the FFLONK proof structure output by SnarkJS.
+type CircomFflonkProof struct {
+	Polynomials struct {
+		C1 []string `json:"C1"`
+		C2 []string `json:"C2"`
+		W1 []string `json:"W1"`
+		W2 []string `json:"W2"`
+	} `json:"polynomials"`
+	Evaluations struct {
+		Ql  string `json:"ql"`
+		Qr  string `json:"qr"`
+		Qm  string `json:"qm"`
+		Qo  string `json:"qo"`
+		Qc  string `json:"qc"`
+		S1  string `json:"s1"`
+		S2  string `json:"s2"`
+		S3  string `json:"s3"`
+		A   string `json:"a"`
+		B   string `json:"b"`
+		C   string `json:"c"`
+		Z   string `json:"z"`
+		Zw  string `json:"zw"`
+		T1w string `json:"t1w"`
+		T2w string `json:"t2w"`
+		Inv string `json:"inv"` // Only used by the Solidity verifier
+	} `json:"evaluations"`
+	Protocol string `json:"protocol"`
+	Curve    string `json:"curve"`
+}
+
+// CircomFflonkVerificationKey represents the FFLONK verification key
+// structure output by SnarkJS.
+type CircomFflonkVerificationKey struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Power    int        `json:"power"`
+	K1       string     `json:"k1"`
+	K2       string     `json:"k2"`
+	W        string     `json:"w"`
+	W3       string     `json:"w3"`
+	W4       string     `json:"w4"`
+	W8       string     `json:"w8"`
+	Wr       string     `json:"wr"`
+	X2       [][]string `json:"X_2"`
+	C0       []string   `json:"C0"`
+}
+
+// fflonkProofBN254 holds a parsed FFLONK proof.
+type fflonkProofBN254 struct {
+	C1, C2, W1, W2 bn254.G1Affine
+	// evaluations, in transcript order
+	ql, qr, qm, qo, qc, s1, s2, s3, a, b, c, z, zw, t1w, t2w bn254fr.Element
+}
+
+// fflonkVerificationKeyBN254 holds a parsed FFLONK verification key.
+type fflonkVerificationKeyBN254 struct {
+	nPublic           int
+	power             int
+	k1, k2            bn254fr.Element
+	w, w3, w4, w8, wr bn254fr.Element
+	X2                bn254.G2Affine
+	C0                bn254.G1Affine
+}
+
+// fflonkChallenges holds the Fiat-Shamir challenges and the opening sets
+// S0 = {h0 w8^i}, S1 = {h1 w4^i} and S2 = {h2 w3^i} U {h3 w3^i}.
+type fflonkChallenges struct {
+	beta, gamma, xiSeed, xi, xiw, alpha, y bn254fr.Element
+	xin, zh                                bn254fr.Element
+	h0w8                                   [8]bn254fr.Element
+	h1w4                                   [4]bn254fr.Element
+	h2w3, h3w3                             [3]bn254fr.Element
+}
+
+// UnmarshalCircomFflonkProofJSON unmarshals a SnarkJS FFLONK proof JSON string.
+func UnmarshalCircomFflonkProofJSON(rawProof []byte) (*CircomFflonkProof, error) {
+	var proof CircomFflonkProof
+	if err := json.Unmarshal(rawProof, &proof); err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+// UnmarshalCircomFflonkVerificationKeyJSON unmarshals a SnarkJS FFLONK
+// verification key JSON string.
+func UnmarshalCircomFflonkVerificationKeyJSON(rawVerificationKey []byte) (*CircomFflonkVerificationKey, error) {
+	var verificationKey CircomFflonkVerificationKey
+	if err := json.Unmarshal(rawVerificationKey, &verificationKey); err != nil {
+		return nil, err
+	}
+	return &verificationKey, nil
+}
+
+// VerifyCircomFflonkProofBN254 verifies a SnarkJS FFLONK proof over BN254.
+func VerifyCircomFflonkProofBN254(vkey []byte, rawProof string, pubSignals []string) (bool, error) {
+	circomVk, err := UnmarshalCircomFflonkVerificationKeyJSON(vkey)
+	if err != nil {
+		return false, err
+	}
+	circomProof, err := UnmarshalCircomFflonkProofJSON([]byte(rawProof))
+	if err != nil {
+		return false, fmt.Errorf("failed to unmarshal circom proof: %v", err)
+	}
+	return circomProof.VerifyBN254(circomVk, pubSignals)
+}
+
+// VerifyBN254 verifies the FFLONK proof natively over BN254, replicating the
+// SnarkJS verifier: the committed polynomials C0, C1 and C2 are opened on the
+// sets S0, S1 and S2 with a single batched KZG pairing check.
+func (circomProof *CircomFflonkProof) VerifyBN254(circomVk *CircomFflonkVerificationKey, pubSignals []string) (bool, error) {
+	if circomVk.Protocol != ProtocolFflonk || (circomProof.Protocol != "" && circomProof.Protocol != ProtocolFflonk) {
+		return false, fmt.Errorf("unexpected protocol: vkey %q, proof %q", circomVk.Protocol, circomProof.Protocol)
+	}
+	vk, err := circomVk.parseBN254()
+	if err != nil {
+		return false, err
+	}
+	proof, err := circomProof.parseBN254()
+	if err != nil {
+		return false, err
+	}
+	if len(pubSignals) != vk.nPublic {
+		return false, fmt.Errorf("invalid number of public inputs: got %d, want %d", len(pubSignals), vk.nPublic)
+	}
+	publicInputs, err := ConvertPublicInputsBN254(pubSignals)
+	if err != nil {
+		return false, err
+	}
+
+	ch := fflonkCalculateChallenges(proof, vk, publicInputs)
+	if ch.zh.IsZero() {
+		return false, fmt.Errorf("proof verification failed: xi is in the evaluation domain")
+	}
+	l := fflonkLagrangeEvaluations(&ch, vk)
+
+	// PI(xi) = -sum(w_i * L_i(xi))
+	var pi, tmp bn254fr.Element
+	for i := range publicInputs {
+		tmp.Mul(&publicInputs[i], &l[i+1])
+		pi.Sub(&pi, &tmp)
+	}
+
+	r0, err := fflonkComputeR0(proof, &ch)
+	if err != nil {
+		return false, err
+	}
+	r1, err := fflonkComputeR1(proof, &ch, &pi)
+	if err != nil {
+		return false, err
+	}
+	r2, err := fflonkComputeR2(proof, &ch, vk, &l[1])
+	if err != nil {
+		return false, err
+	}
+
+	// Z_S0(y), Z_S1(y) and Z_S2(y) are the vanishing polynomials of the
+	// opening sets evaluated at y.
+	zs0, zs1, zs2 := vanishingAt(&ch.y, ch.h0w8[:]...), vanishingAt(&ch.y, ch.h1w4[:]...), vanishingAt(&ch.y, ch.h2w3[:]...)
+	tmp = vanishingAt(&ch.y, ch.h3w3[:]...)
+	zs2.Mul(&zs2, &tmp)
+	if zs1.IsZero() || zs2.IsZero() {
+		return false, fmt.Errorf("proof verification failed: y is in the opening sets")
+	}
+	// quotient1 = alpha * Z_S0(y) / Z_S1(y), quotient2 = alpha^2 * Z_S0(y) / Z_S2(y)
+	var quotient1, quotient2 bn254fr.Element
+	quotient1.Div(&zs0, &zs1).Mul(&quotient1, &ch.alpha)
+	quotient2.Div(&zs0, &zs2).Mul(&quotient2, &ch.alpha).Mul(&quotient2, &ch.alpha)
+
+	// F = C0 + quotient1*C1 + quotient2*C2
+	f := vk.C0
+	f.Add(&f, g1Mul(&proof.C1, &quotient1))
+	f.Add(&f, g1Mul(&proof.C2, &quotient2))
+
+	// E = (r0 + quotient1*r1 + quotient2*r2) * G1
+	var e bn254fr.Element
+	e.Mul(&r1, &quotient1)
+	tmp.Mul(&r2, &quotient2)
+	e.Add(&e, &tmp).Add(&e, &r0)
+	_, _, g1, g2 := bn254.Generators()
+
+	// J = Z_S0(y) * W1
+	j := g1Mul(&proof.W1, &zs0)
+
+	// A1 = F - E - J + y*W2
+	a1 := f
+	a1.Sub(&a1, g1Mul(&g1, &e))
+	a1.Sub(&a1, j)
+	a1.Add(&a1, g1Mul(&proof.W2, &ch.y))
+
+	// e(-A1, G2) * e(W2, X_2) == 1
+	a1.Neg(&a1)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{a1, proof.W2}, []bn254.G2Affine{g2, vk.X2})
+	if err != nil {
+		return false, fmt.Errorf("pairing check failed: %v", err)
+	}
+	if !ok {
+		return false, fmt.Errorf("proof verification failed: invalid pairing")
+	}
+	return true, nil
+}
+
+// fflonkCalculateChallenges derives beta, gamma, xi, alpha and y and the
+// opening sets exactly as the SnarkJS FFLONK verifier does.
+func fflonkCalculateChallenges(proof *fflonkProofBN254, vk *fflonkVerificationKeyBN254,
+	publicInputs []bn254fr.Element,
+) fflonkChallenges {
+	var ch fflonkChallenges
+	t := &keccakTranscript{}
+	// round 1: beta and gamma
+	t.addPolCommitment(&vk.C0)
+	for i := range publicInputs {
+		t.addScalar(&publicInputs[i])
+	}
+	t.addPolCommitment(&proof.C1)
+	ch.beta = t.getChallenge()
+
+	t.reset()
+	t.addScalar(&ch.beta)
+	ch.gamma = t.getChallenge()
+
+	// round 2: xi seed, from which the opening sets and xi = seed^24 derive
+	t.reset()
+	t.addScalar(&ch.gamma)
+	t.addPolCommitment(&proof.C2)
+	ch.xiSeed = t.getChallenge()
+
+	var seed2 bn254fr.Element
+	seed2.Square(&ch.xiSeed)
+	// h0 = seed^3, h1 = seed^6, h2 = seed^8 and h3 = h2 * wr
+	ch.h0w8[0].Mul(&seed2, &ch.xiSeed)
+	ch.h1w4[0].Square(&ch.h0w8[0])
+	ch.h2w3[0].Mul(&ch.h1w4[0], &seed2)
+	ch.h3w3[0].Mul(&ch.h2w3[0], &vk.wr)
+	for i := 1; i < len(ch.h0w8); i++ {
+		ch.h0w8[i].Mul(&ch.h0w8[i-1], &vk.w8)
+	}
+	for i := 1; i < len(ch.h1w4); i++ {
+		ch.h1w4[i].Mul(&ch.h1w4[i-1], &vk.w4)
+	}
+	for i := 1; i < len(ch.h2w3); i++ {
+		ch.h2w3[i].Mul(&ch.h2w3[i-1], &vk.w3)
+		ch.h3w3[i].Mul(&ch.h3w3[i-1], &vk.w3)
+	}
+	ch.xi.Square(&ch.h2w3[0]).Mul(&ch.xi, &ch.h2w3[0])
+	ch.xiw.Mul(&ch.xi, &vk.w)
+	ch.xin = ch.xi
+	for i := 0; i < vk.power; i++ {
+		ch.xin.Square(&ch.xin)
+	}
+	var one bn254fr.Element
+	one.SetOne()
+	ch.zh.Sub(&ch.xin, &one)
+
+	// round 3: alpha
+	t.reset()
+	t.addScalar(&ch.xiSeed)
+	for _, s := range proof.evaluations() {
+		t.addScalar(s)
+	}
+	ch.alpha = t.getChallenge()
+
+	// round 4: y
+	t.reset()
+	t.addScalar(&ch.alpha)
+	t.addPolCommitment(&proof.W1)
+	ch.y = t.getChallenge()
+	return ch
+}
+
+// fflonkLagrangeEvaluations computes the Lagrange basis evaluations
+// L_1(xi)..L_max(1,nPublic)(xi). The returned slice is 1-indexed.
+func fflonkLagrangeEvaluations(ch *fflonkChallenges, vk *fflonkVerificationKeyBN254) []bn254fr.Element {
+	var n bn254fr.Element
+	n.SetUint64(1 << vk.power)
+	l := make([]bn254fr.Element, max(1, vk.nPublic)+1)
+	var w bn254fr.Element
+	w.SetOne()
+	for i := 1; i < len(l); i++ {
+		var num, den bn254fr.Element
+		num.Mul(&w, &ch.zh)
+		den.Sub(&ch.xi, &w).Mul(&den, &n)
+		l[i].Div(&num, &den)
+		w.Mul(&w, &vk.w)
+	}
+	return l
+}
+
+// fflonkComputeR0 evaluates at y the polynomial interpolating
+// C0(X) = ql(X^8) + X qr(X^8) + X^2 qo(X^8) + X^3 qm(X^8) + X^4 qc(X^8)
+// + X^5 s1(X^8) + X^6 s2(X^8) + X^7 s3(X^8) over S0.
+func fflonkComputeR0(proof *fflonkProofBN254, ch *fflonkChallenges) (bn254fr.Element, error) {
+	coeffs := []bn254fr.Element{proof.ql, proof.qr, proof.qo, proof.qm, proof.qc, proof.s1, proof.s2, proof.s3}
+	values := make([]bn254fr.Element, len(ch.h0w8))
+	for i := range ch.h0w8 {
+		values[i] = evalPolynomial(coeffs, &ch.h0w8[i])
+	}
+	return interpolateAt(ch.h0w8[:], values, &ch.y)
+}
+
+// fflonkComputeR1 evaluates at y the polynomial interpolating
+// C1(X) = a(X^4) + X b(X^4) + X^2 c(X^4) + X^3 T0(X^4) over S1, where
+// T0(xi) = (ql a + qr b + qm a b + qo c + qc + PI(xi)) / Z_H(xi).
+func fflonkComputeR1(proof *fflonkProofBN254, ch *fflonkChallenges, pi *bn254fr.Element) (bn254fr.Element, error) {
+	var t0, tmp bn254fr.Element
+	t0.Mul(&proof.ql, &proof.a)
+	tmp.Mul(&proof.qr, &proof.b)
+	t0.Add(&t0, &tmp)
+	tmp.Mul(&proof.a, &proof.b).Mul(&tmp, &proof.qm)
+	t0.Add(&t0, &tmp)
+	tmp.Mul(&proof.qo, &proof.c)
+	t0.Add(&t0, &tmp).Add(&t0, &proof.qc).Add(&t0, pi)
+	t0.Div(&t0, &ch.zh)
+
+	coeffs := []bn254fr.Element{proof.a, proof.b, proof.c, t0}
+	values := make([]bn254fr.Element, len(ch.h1w4))
+	for i := range ch.h1w4 {
+		values[i] = evalPolynomial(coeffs, &ch.h1w4[i])
+	}
+	return interpolateAt(ch.h1w4[:], values, &ch.y)
+}
+
+// fflonkComputeR2 evaluates at y the polynomial interpolating
+// C2(X) = z(X^3) + X T1(X^3) + X^2 T2(X^3) over S2, where
+// T1(xi) = (z - 1) L_1(xi) / Z_H(xi) and T2(xi) is the permutation argument
+// divided by Z_H(xi). On h3 w3^i, C2 is given by z(xi w), T1(xi w) and T2(xi w).
+func fflonkComputeR2(proof *fflonkProofBN254, ch *fflonkChallenges, vk *fflonkVerificationKeyBN254,
+	l1 *bn254fr.Element,
+) (bn254fr.Element, error) {
+	var one, t1, t2, tmp bn254fr.Element
+	one.SetOne()
+	t1.Sub(&proof.z, &one).Mul(&t1, l1).Div(&t1, &ch.zh)
+
+	var betaxi, t211, t212, t213, t221, t222, t223, t21, t22 bn254fr.Element
+	betaxi.Mul(&ch.beta, &ch.xi)
+	t211.Add(&proof.a, &betaxi).Add(&t211, &ch.gamma)
+	tmp.Mul(&betaxi, &vk.k1)
+	t212.Add(&proof.b, &tmp).Add(&t212, &ch.gamma)
+	tmp.Mul(&betaxi, &vk.k2)
+	t213.Add(&proof.c, &tmp).Add(&t213, &ch.gamma)
+	t21.Mul(&t211, &t212).Mul(&t21, &t213).Mul(&t21, &proof.z)
+	t221.Mul(&ch.beta, &proof.s1).Add(&t221, &proof.a).Add(&t221, &ch.gamma)
+	t222.Mul(&ch.beta, &proof.s2).Add(&t222, &proof.b).Add(&t222, &ch.gamma)
+	t223.Mul(&ch.beta, &proof.s3).Add(&t223, &proof.c).Add(&t223, &ch.gamma)
+	t22.Mul(&t221, &t222).Mul(&t22, &t223).Mul(&t22, &proof.zw)
+	t2.Sub(&t21, &t22).Div(&t2, &ch.zh)
+
+	points := append(append([]bn254fr.Element{}, ch.h2w3[:]...), ch.h3w3[:]...)
+	values := make([]bn254fr.Element, 0, len(points))
+	for i := range ch.h2w3 {
+		values = append(values, evalPolynomial([]bn254fr.Element{proof.z, t1, t2}, &ch.h2w3[i]))
+	}
+	for i := range ch.h3w3 {
+		values = append(values, evalPolynomial([]bn254fr.Element{proof.zw, proof.t1w, proof.t2w}, &ch.h3w3[i]))
+	}
+	return interpolateAt(points, values, &ch.y)
+}
+
+// evaluations returns the proof evaluations in transcript order.
+func (proof *fflonkProofBN254) evaluations() []*bn254fr.Element {
+	return []*bn254fr.Element{
+		&proof.ql, &proof.qr, &proof.qm, &proof.qo, &proof.qc, &proof.s1, &proof.s2, &proof.s3,
+		&proof.a, &proof.b, &proof.c, &proof.z, &proof.zw, &proof.t1w, &proof.t2w,
+	}
+}
+
+// parseBN254 converts the JSON proof into curve points and field elements.
+func (circomProof *CircomFflonkProof) parseBN254() (*fflonkProofBN254, error) {
+	proof := &fflonkProofBN254{}
+	points := []struct {
+		name string
+		raw  []string
+		dst  *bn254.G1Affine
+	}{
+		{"C1", circomProof.Polynomials.C1, &proof.C1}, {"C2", circomProof.Polynomials.C2, &proof.C2},
+		{"W1", circomProof.Polynomials.W1, &proof.W1}, {"W2", circomProof.Polynomials.W2, &proof.W2},
+	}
+	for _, p := range points {
+		g1, err := stringToG1BN254(p.raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %v", p.name, err)
+		}
+		*p.dst = *g1
+	}
+	ev := &circomProof.Evaluations
+	raw := []struct{ name, value string }{
+		{"ql", ev.Ql}, {"qr", ev.Qr}, {"qm", ev.Qm}, {"qo", ev.Qo}, {"qc", ev.Qc},
+		{"s1", ev.S1}, {"s2", ev.S2}, {"s3", ev.S3}, {"a", ev.A}, {"b", ev.B}, {"c", ev.C},
+		{"z", ev.Z}, {"zw", ev.Zw}, {"t1w", ev.T1w}, {"t2w", ev.T2w},
+	}
+	for i, dst := range proof.evaluations() {
+		if err := stringToFrBN254(raw[i].value, dst); err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %v", raw[i].name, err)
+		}
+	}
+	return proof, nil
+}
+
+// parseBN254 converts the JSON verification key into curve points and field
+// elements, checking that the roots of unity have the expected orders.
+func (circomVk *CircomFflonkVerificationKey) parseBN254() (*fflonkVerificationKeyBN254, error) {
+	if curve := circomVk.Curve; curve != "" && curve != CurveBN254 && curve != "bn254" {
+		return nil, fmt.Errorf("unsupported curve: %s", curve)
+	}
+	if circomVk.Power <= 0 || circomVk.Power > maxOrderRootBN254 {
+		return nil, fmt.Errorf("invalid domain power: %d", circomVk.Power)
+	}
+	if circomVk.NPublic < 0 {
+		return nil, fmt.Errorf("invalid number of public inputs: %d", circomVk.NPublic)
+	}
+	vk := &fflonkVerificationKeyBN254{
+		nPublic: circomVk.NPublic,
+		power:   circomVk.Power,
+	}
+	scalars := []struct {
+		name string
+		raw  string
+		dst  *bn254fr.Element
+	}{
+		{"k1", circomVk.K1, &vk.k1}, {"k2", circomVk.K2, &vk.k2}, {"w", circomVk.W, &vk.w},
+		{"w3", circomVk.W3, &vk.w3}, {"w4", circomVk.W4, &vk.w4}, {"w8", circomVk.W8, &vk.w8},
+		{"wr", circomVk.Wr, &vk.wr},
+	}
+	for _, s := range scalars {
+		if err := stringToFrBN254(s.raw, s.dst); err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %v", s.name, err)
+		}
+	}
+	if w := rootOfUnityBN254(circomVk.Power); !w.Equal(&vk.w) {
+		return nil, fmt.Errorf("unexpected root of unity for domain power %d", circomVk.Power)
+	}
+	// w3, w4 and w8 must be primitive roots of unity and wr a cube root of w
+	var one, minusOne, tmp bn254fr.Element
+	one.SetOne()
+	minusOne.Neg(&one)
+	if tmp.Square(&vk.w3).Mul(&tmp, &vk.w3); !tmp.Equal(&one) || vk.w3.Equal(&one) {
+		return nil, fmt.Errorf("w3 is not a primitive cube root of unity")
+	}
+	if tmp.Square(&vk.w4); !tmp.Equal(&minusOne) {
+		return nil, fmt.Errorf("w4 is not a primitive 4th root of unity")
+	}
+	if tmp.Square(&vk.w8).Square(&tmp); !tmp.Equal(&minusOne) {
+		return nil, fmt.Errorf("w8 is not a primitive 8th root of unity")
+	}
+	if tmp.Square(&vk.wr).Mul(&tmp, &vk.wr); !tmp.Equal(&vk.w) {
+		return nil, fmt.Errorf("wr is not a cube root of w")
+	}
+	c0, err := stringToG1BN254(circomVk.C0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert C0: %v", err)
+	}
+	vk.C0 = *c0
+	x2, err := stringToG2BN254(circomVk.X2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert X_2: %v", err)
+	}
+	vk.X2 = *x2
+	return vk, nil
+}
+
+// evalPolynomial evaluates the polynomial with the given coefficients at x.
+func evalPolynomial(coeffs []bn254fr.Element, x *bn254fr.Element) bn254fr.Element {
+	var res bn254fr.Element
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res.Mul(&res, x).Add(&res, &coeffs[i])
+	}
+	return res
+}
+
+// vanishingAt evaluates prod(y - x_i) over the given points.
+func vanishingAt(y *bn254fr.Element, points ...bn254fr.Element) bn254fr.Element {
+	var res, tmp bn254fr.Element
+	res.SetOne()
+	for i := range points {
+		tmp.Sub(y, &points[i])
+		res.Mul(&res, &tmp)
+	}
+	return res
+}
+
+// interpolateAt evaluates at y the polynomial of degree < len(points) that
+// takes the given values on the points, using the Lagrange basis.
+func interpolateAt(points, values []bn254fr.Element, y *bn254fr.Element) (bn254fr.Element, error) {
+	var res bn254fr.Element
+	for i := range points {
+		num, den := bn254fr.One(), bn254fr.One()
+		for j := range points {
+			if i == j {
+				continue
+			}
+			var tmp bn254fr.Element
+			tmp.Sub(y, &points[j])
+			num.Mul(&num, &tmp)
+			tmp.Sub(&points[i], &points[j])
+			den.Mul(&den, &tmp)
+		}
+		if den.IsZero() {
+			return bn254fr.Element{}, fmt.Errorf("proof verification failed: opening points are not distinct")
+		}
+		num.Div(&num, &den).Mul(&num, &values[i])
+		res.Add(&res, &num)
+	}
+	return res, nil
+}
@@ -0,0 +1,463 @@
+package circom2gnark
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// maxOrderRootBN254 is the 2-adicity of the BN254 scalar field: 2^28 is the
+// largest power of two dividing r-1.
+const maxOrderRootBN254 = 28
+
+// CircomPlonkProof represents the PLONK proof structure output by SnarkJS.
+type CircomPlonkProof struct {
+	A        []string `json:"A"`
+	B        []string `json:"B"`
+	C        []string `json:"C"`
+	Z        []string `json:"Z"`
+	T1       []string `json:"T1"`
+	T2       []string `json:"T2"`
+	T3       []string `json:"T3"`
+	Wxi      []string `json:"Wxi"`
+	Wxiw     []string `json:"Wxiw"`
+	EvalA    string   `json:"eval_a"`
+	EvalB    string   `json:"eval_b"`
+	EvalC    string   `json:"eval_c"`
+	EvalS1   string   `json:"eval_s1"`
+	EvalS2   string   `json:"eval_s2"`
+	EvalZw   string   `json:"eval_zw"`
+	Protocol string   `json:"protocol"`
+	Curve    string   `json:"curve"`
+}
+
+// CircomPlonkVerificationKey represents the PLONK verification key structure
+// output by SnarkJS.
+type CircomPlonkVerificationKey struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Power    int        `json:"power"`
+	K1       string     `json:"k1"`
+	K2       string     `json:"k2"`
+	Qm       []string   `json:"Qm"`
+	Ql       []string   `json:"Ql"`
+	Qr       []string   `json:"Qr"`
+	Qo       []string   `json:"Qo"`
+	Qc       []string   `json:"Qc"`
+	S1       []string   `json:"S1"`
+	S2       []string   `json:"S2"`
+	S3       []string   `json:"S3"`
+	X2       [][]string `json:"X_2"`
+	W        string     `json:"w"`
+}
+
+// plonkProofBN254 holds a parsed PLONK proof.
+type plonkProofBN254 struct {
+	A, B, C, Z, T1, T2, T3, Wxi, Wxiw           bn254.G1Affine
+	evalA, evalB, evalC, evalS1, evalS2, evalZw bn254fr.Element
+}
+
+// plonkVerificationKeyBN254 holds a parsed PLONK verification key.
+type plonkVerificationKeyBN254 struct {
+	nPublic                        int
+	power                          int
+	k1, k2                         bn254fr.Element
+	Qm, Ql, Qr, Qo, Qc, S1, S2, S3 bn254.G1Affine
+	X2                             bn254.G2Affine
+	w                              bn254fr.Element
+}
+
+// plonkChallenges holds the Fiat-Shamir challenges and the derived values
+// used by the verifier.
+type plonkChallenges struct {
+	beta, gamma, alpha, xi, u bn254fr.Element
+	v                         [6]bn254fr.Element
+	xin, zh                   bn254fr.Element
+}
+
+// UnmarshalCircomPlonkProofJSON unmarshals a SnarkJS PLONK proof JSON string.
+func UnmarshalCircomPlonkProofJSON(rawProof []byte) (*CircomPlonkProof, error) {
+	var proof CircomPlonkProof
+	if err := json.Unmarshal(rawProof, &proof); err != nil {
+		return nil, err
+	}
+	return &proof, nil
+}
+
+// UnmarshalCircomPlonkVerificationKeyJSON unmarshals a SnarkJS PLONK
+// verification key JSON string.
+func UnmarshalCircomPlonkVerificationKeyJSON(rawVerificationKey []byte) (*CircomPlonkVerificationKey, error) {
+	var verificationKey CircomPlonkVerificationKey
+	if err := json.Unmarshal(rawVerificationKey, &verificationKey); err != nil {
+		return nil, err
+	}
+	return &verificationKey, nil
+}
+
+// VerifyCircomPlonkProofBN254 verifies a SnarkJS PLONK proof over BN254.
+func VerifyCircomPlonkProofBN254(vkey []byte, rawProof string, pubSignals []string) (bool, error) {
+	circomVk, err := UnmarshalCircomPlonkVerificationKeyJSON(vkey)
+	if err != nil {
+		return false, err
+	}
+	circomProof, err := UnmarshalCircomPlonkProofJSON([]byte(rawProof))
+	if err != nil {
+		return false, fmt.Errorf("failed to unmarshal circom proof: %v", err)
+	}
+	return circomProof.VerifyBN254(circomVk, pubSignals)
+}
+
+// VerifyBN254 verifies the PLONK proof natively over BN254, replicating the
+// SnarkJS verifier: challenges are derived with the Keccak-256 transcript and
+// the opening proofs are checked with a single pairing equation.
+func (circomProof *CircomPlonkProof) VerifyBN254(circomVk *CircomPlonkVerificationKey, pubSignals []string) (bool, error) {
+	if circomVk.Protocol != ProtocolPlonk || (circomProof.Protocol != "" && circomProof.Protocol != ProtocolPlonk) {
+		return false, fmt.Errorf("unexpected protocol: vkey %q, proof %q", circomVk.Protocol, circomProof.Protocol)
+	}
+	vk, err := circomVk.parseBN254()
+	if err != nil {
+		return false, err
+	}
+	proof, err := circomProof.parseBN254()
+	if err != nil {
+		return false, err
+	}
+	if len(pubSignals) != vk.nPublic {
+		return false, fmt.Errorf("invalid number of public inputs: got %d, want %d", len(pubSignals), vk.nPublic)
+	}
+	publicInputs, err := ConvertPublicInputsBN254(pubSignals)
+	if err != nil {
+		return false, err
+	}
+
+	ch := plonkCalculateChallenges(proof, vk, publicInputs)
+	l := plonkLagrangeEvaluations(&ch, vk)
+
+	// PI(xi) = -sum(w_i * L_i(xi))
+	var pi, tmp bn254fr.Element
+	for i := range publicInputs {
+		tmp.Mul(&publicInputs[i], &l[i+1])
+		pi.Sub(&pi, &tmp)
+	}
+
+	r0 := plonkCalculateR0(proof, &ch, &pi, &l[1])
+	d := plonkComputeD(proof, &ch, vk, &l[1])
+
+	// F = D + v1*A + v2*B + v3*C + v4*S1 + v5*S2
+	f := *d
+	f.Add(&f, g1Mul(&proof.A, &ch.v[1]))
+	f.Add(&f, g1Mul(&proof.B, &ch.v[2]))
+	f.Add(&f, g1Mul(&proof.C, &ch.v[3]))
+	f.Add(&f, g1Mul(&vk.S1, &ch.v[4]))
+	f.Add(&f, g1Mul(&vk.S2, &ch.v[5]))
+
+	// E = (-r0 + v1*a + v2*b + v3*c + v4*s1 + v5*s2 + u*zw) * G1
+	var e bn254fr.Element
+	e.Neg(&r0)
+	for _, term := range [][2]*bn254fr.Element{
+		{&ch.v[1], &proof.evalA}, {&ch.v[2], &proof.evalB}, {&ch.v[3], &proof.evalC},
+		{&ch.v[4], &proof.evalS1}, {&ch.v[5], &proof.evalS2}, {&ch.u, &proof.evalZw},
+	} {
+		tmp.Mul(term[0], term[1])
+		e.Add(&e, &tmp)
+	}
+	_, _, g1, g2 := bn254.Generators()
+	eG1 := g1Mul(&g1, &e)
+
+	// A1 = Wxi + u*Wxiw
+	a1 := proof.Wxi
+	a1.Add(&a1, g1Mul(&proof.Wxiw, &ch.u))
+	// B1 = xi*Wxi + u*xi*w*Wxiw + F - E
+	var s bn254fr.Element
+	s.Mul(&ch.u, &ch.xi).Mul(&s, &vk.w)
+	b1 := *g1Mul(&proof.Wxi, &ch.xi)
+	b1.Add(&b1, g1Mul(&proof.Wxiw, &s))
+	b1.Add(&b1, &f)
+	b1.Sub(&b1, eG1)
+
+	// e(-A1, X_2) * e(B1, G2) == 1
+	a1.Neg(&a1)
+	ok, err := bn254.PairingCheck([]bn254.G1Affine{a1, b1}, []bn254.G2Affine{vk.X2, g2})
+	if err != nil {
+		return false, fmt.Errorf("pairing check failed: %v", err)
+	}
+	if !ok {
+		return false, fmt.Errorf("proof verification failed: invalid pairing")
+	}
+	return true, nil
+}
+
+// plonkCalculateChallenges derives beta, gamma, alpha, xi, v and u exactly as
+// the SnarkJS PLONK verifier does.
+func plonkCalculateChallenges(proof *plonkProofBN254, vk *plonkVerificationKeyBN254,
+	publicInputs []bn254fr.Element,
+) plonkChallenges {
+	var ch plonkChallenges
+	t := &keccakTranscript{}
+	// round 2: beta and gamma
+	for _, p := range []*bn254.G1Affine{&vk.Qm, &vk.Ql, &vk.Qr, &vk.Qo, &vk.Qc, &vk.S1, &vk.S2, &vk.S3} {
+		t.addPolCommitment(p)
+	}
+	for i := range publicInputs {
+		t.addScalar(&publicInputs[i])
+	}
+	t.addPolCommitment(&proof.A)
+	t.addPolCommitment(&proof.B)
+	t.addPolCommitment(&proof.C)
+	ch.beta = t.getChallenge()
+
+	t.reset()
+	t.addScalar(&ch.beta)
+	ch.gamma = t.getChallenge()
+
+	// round 3: alpha
+	t.reset()
+	t.addScalar(&ch.beta)
+	t.addScalar(&ch.gamma)
+	t.addPolCommitment(&proof.Z)
+	ch.alpha = t.getChallenge()
+
+	// round 4: xi
+	t.reset()
+	t.addScalar(&ch.alpha)
+	t.addPolCommitment(&proof.T1)
+	t.addPolCommitment(&proof.T2)
+	t.addPolCommitment(&proof.T3)
+	ch.xi = t.getChallenge()
+
+	// round 5: v
+	t.reset()
+	t.addScalar(&ch.xi)
+	for _, s := range []*bn254fr.Element{&proof.evalA, &proof.evalB, &proof.evalC, &proof.evalS1, &proof.evalS2, &proof.evalZw} {
+		t.addScalar(s)
+	}
+	ch.v[1] = t.getChallenge()
+	for i := 2; i < len(ch.v); i++ {
+		ch.v[i].Mul(&ch.v[i-1], &ch.v[1])
+	}
+
+	// opening batch challenge: u
+	t.reset()
+	t.addPolCommitment(&proof.Wxi)
+	t.addPolCommitment(&proof.Wxiw)
+	ch.u = t.getChallenge()
+	return ch
+}
+
+// plonkLagrangeEvaluations computes xi^n, Z_H(xi) and the Lagrange basis
+// evaluations L_1(xi)..L_max(1,nPublic)(xi). The returned slice is 1-indexed.
+func plonkLagrangeEvaluations(ch *plonkChallenges, vk *plonkVerificationKeyBN254) []bn254fr.Element {
+	ch.xin = ch.xi
+	for i := 0; i < vk.power; i++ {
+		ch.xin.Square(&ch.xin)
+	}
+	var one bn254fr.Element
+	one.SetOne()
+	ch.zh.Sub(&ch.xin, &one)
+
+	var n bn254fr.Element
+	n.SetUint64(1 << vk.power)
+	l := make([]bn254fr.Element, max(1, vk.nPublic)+1)
+	w := one
+	for i := 1; i < len(l); i++ {
+		var num, den bn254fr.Element
+		num.Mul(&w, &ch.zh)
+		den.Sub(&ch.xi, &w).Mul(&den, &n)
+		l[i].Div(&num, &den)
+		w.Mul(&w, &vk.w)
+	}
+	return l
+}
+
+// plonkCalculateR0 computes the constant term of the linearisation polynomial.
+func plonkCalculateR0(proof *plonkProofBN254, ch *plonkChallenges, pi, l1 *bn254fr.Element) bn254fr.Element {
+	var e2, e3, e3a, e3b, e3c, r0 bn254fr.Element
+	e2.Square(&ch.alpha).Mul(&e2, l1)
+
+	e3a.Mul(&ch.beta, &proof.evalS1).Add(&e3a, &proof.evalA).Add(&e3a, &ch.gamma)
+	e3b.Mul(&ch.beta, &proof.evalS2).Add(&e3b, &proof.evalB).Add(&e3b, &ch.gamma)
+	e3c.Add(&proof.evalC, &ch.gamma)
+	e3.Mul(&e3a, &e3b).Mul(&e3, &e3c).Mul(&e3, &proof.evalZw).Mul(&e3, &ch.alpha)
+
+	r0.Sub(pi, &e2).Sub(&r0, &e3)
+	return r0
+}
+
+// plonkComputeD computes the commitment to the linearisation polynomial plus
+// the u-scaled commitment to Z, as [D]_1 in the SnarkJS verifier.
+func plonkComputeD(proof *plonkProofBN254, ch *plonkChallenges, vk *plonkVerificationKeyBN254, l1 *bn254fr.Element) *bn254.G1Affine {
+	var tmp bn254fr.Element
+	// d1 = Qm*a*b + Ql*a + Qr*b + Qo*c + Qc
+	tmp.Mul(&proof.evalA, &proof.evalB)
+	d1 := *g1Mul(&vk.Qm, &tmp)
+	d1.Add(&d1, g1Mul(&vk.Ql, &proof.evalA))
+	d1.Add(&d1, g1Mul(&vk.Qr, &proof.evalB))
+	d1.Add(&d1, g1Mul(&vk.Qo, &proof.evalC))
+	d1.Add(&d1, &vk.Qc)
+
+	// d2 = Z * ((a + beta*xi + gamma)(b + beta*k1*xi + gamma)(c + beta*k2*xi + gamma)*alpha + L1*alpha^2 + u)
+	var betaxi, d2a1, d2a2, d2a3, d2a, d2b, d2s bn254fr.Element
+	betaxi.Mul(&ch.beta, &ch.xi)
+	d2a1.Add(&proof.evalA, &betaxi).Add(&d2a1, &ch.gamma)
+	tmp.Mul(&betaxi, &vk.k1)
+	d2a2.Add(&proof.evalB, &tmp).Add(&d2a2, &ch.gamma)
+	tmp.Mul(&betaxi, &vk.k2)
+	d2a3.Add(&proof.evalC, &tmp).Add(&d2a3, &ch.gamma)
+	d2a.Mul(&d2a1, &d2a2).Mul(&d2a, &d2a3).Mul(&d2a, &ch.alpha)
+	d2b.Square(&ch.alpha).Mul(&d2b, l1)
+	d2s.Add(&d2a, &d2b).Add(&d2s, &ch.u)
+	d2 := g1Mul(&proof.Z, &d2s)
+
+	// d3 = S3 * (a + beta*s1 + gamma)(b + beta*s2 + gamma)*alpha*beta*zw
+	var d3a, d3b, d3c, d3s bn254fr.Element
+	d3a.Mul(&ch.beta, &proof.evalS1).Add(&d3a, &proof.evalA).Add(&d3a, &ch.gamma)
+	d3b.Mul(&ch.beta, &proof.evalS2).Add(&d3b, &proof.evalB).Add(&d3b, &ch.gamma)
+	d3c.Mul(&ch.alpha, &ch.beta).Mul(&d3c, &proof.evalZw)
+	d3s.Mul(&d3a, &d3b).Mul(&d3s, &d3c)
+	d3 := g1Mul(&vk.S3, &d3s)
+
+	// d4 = (T1 + xi^n*T2 + xi^2n*T3) * Z_H(xi)
+	var xin2 bn254fr.Element
+	xin2.Square(&ch.xin)
+	d4 := proof.T1
+	d4.Add(&d4, g1Mul(&proof.T2, &ch.xin))
+	d4.Add(&d4, g1Mul(&proof.T3, &xin2))
+	d4 = *g1Mul(&d4, &ch.zh)
+
+	d := d1
+	d.Add(&d, d2)
+	d.Sub(&d, d3)
+	d.Sub(&d, &d4)
+	return &d
+}
+
+// parseBN254 converts the JSON proof into curve points and field elements.
+func (circomProof *CircomPlonkProof) parseBN254() (*plonkProofBN254, error) {
+	proof := &plonkProofBN254{}
+	points := []struct {
+		name string
+		raw  []string
+		dst  *bn254.G1Affine
+	}{
+		{"A", circomProof.A, &proof.A}, {"B", circomProof.B, &proof.B}, {"C", circomProof.C, &proof.C},
+		{"Z", circomProof.Z, &proof.Z}, {"T1", circomProof.T1, &proof.T1}, {"T2", circomProof.T2, &proof.T2},
+		{"T3", circomProof.T3, &proof.T3}, {"Wxi", circomProof.Wxi, &proof.Wxi}, {"Wxiw", circomProof.Wxiw, &proof.Wxiw},
+	}
+	for _, p := range points {
+		g1, err := stringToG1BN254(p.raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %v", p.name, err)
+		}
+		*p.dst = *g1
+	}
+	scalars := []struct {
+		name string
+		raw  string
+		dst  *bn254fr.Element
+	}{
+		{"eval_a", circomProof.EvalA, &proof.evalA}, {"eval_b", circomProof.EvalB, &proof.evalB},
+		{"eval_c", circomProof.EvalC, &proof.evalC}, {"eval_s1", circomProof.EvalS1, &proof.evalS1},
+		{"eval_s2", circomProof.EvalS2, &proof.evalS2}, {"eval_zw", circomProof.EvalZw, &proof.evalZw},
+	}
+	for _, s := range scalars {
+		if err := stringToFrBN254(s.raw, s.dst); err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %v", s.name, err)
+		}
+	}
+	return proof, nil
+}
+
+// parseBN254 converts the JSON verification key into curve points and field
+// elements.
+func (circomVk *CircomPlonkVerificationKey) parseBN254() (*plonkVerificationKeyBN254, error) {
+	if curve := circomVk.Curve; curve != "" && curve != CurveBN254 && curve != "bn254" {
+		return nil, fmt.Errorf("unsupported curve: %s", curve)
+	}
+	if circomVk.Power <= 0 || circomVk.Power > maxOrderRootBN254 {
+		return nil, fmt.Errorf("invalid domain power: %d", circomVk.Power)
+	}
+	if circomVk.NPublic < 0 {
+		return nil, fmt.Errorf("invalid number of public inputs: %d", circomVk.NPublic)
+	}
+	vk := &plonkVerificationKeyBN254{
+		nPublic: circomVk.NPublic,
+		power:   circomVk.Power,
+		w:       rootOfUnityBN254(circomVk.Power),
+	}
+	points := []struct {
+		name string
+		raw  []string
+		dst  *bn254.G1Affine
+	}{
+		{"Qm", circomVk.Qm, &vk.Qm}, {"Ql", circomVk.Ql, &vk.Ql}, {"Qr", circomVk.Qr, &vk.Qr},
+		{"Qo", circomVk.Qo, &vk.Qo}, {"Qc", circomVk.Qc, &vk.Qc}, {"S1", circomVk.S1, &vk.S1},
+		{"S2", circomVk.S2, &vk.S2}, {"S3", circomVk.S3, &vk.S3},
+	}
+	for _, p := range points {
+		g1, err := stringToG1BN254(p.raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %v", p.name, err)
+		}
+		*p.dst = *g1
+	}
+	if circomVk.W != "" {
+		var w bn254fr.Element
+		if err := stringToFrBN254(circomVk.W, &w); err != nil {
+			return nil, fmt.Errorf("failed to convert w: %v", err)
+		}
+		if !w.Equal(&vk.w) {
+			return nil, fmt.Errorf("unexpected root of unity for domain power %d", circomVk.Power)
+		}
+	}
+	x2, err := stringToG2BN254(circomVk.X2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert X_2: %v", err)
+	}
+	vk.X2 = *x2
+	if err := stringToFrBN254(circomVk.K1, &vk.k1); err != nil {
+		return nil, fmt.Errorf("failed to convert k1: %v", err)
+	}
+	if err := stringToFrBN254(circomVk.K2, &vk.k2); err != nil {
+		return nil, fmt.Errorf("failed to convert k2: %v", err)
+	}
+	return vk, nil
+}
+
+// stringToFrBN254 parses a decimal or hex string into a BN254 scalar.
+func stringToFrBN254(s string, dst *bn254fr.Element) error {
+	bi, err := stringToBigInt(s)
+	if err != nil {
+		return err
+	}
+	dst.SetBigInt(bi)
+	return nil
+}
+
+// g1Mul returns s*p as a new point.
+func g1Mul(p *bn254.G1Affine, s *bn254fr.Element) *bn254.G1Affine {
+	return new(bn254.G1Affine).ScalarMultiplication(p, s.BigInt(new(big.Int)))
+}
+
+// rootOfUnityBN254 returns the 2^power-th root of unity used by SnarkJS
+// (ffjavascript Fr.w[power]): nqr^((r-1)/2^s) squared s-power times, where
+// nqr is the smallest quadratic non-residue and 2^s the largest power of two
+// dividing r-1.
+func rootOfUnityBN254(power int) bn254fr.Element {
+	var nqr bn254fr.Element
+	nqr.SetUint64(2)
+	for nqr.Legendre() != -1 {
+		nqr.Add(&nqr, new(bn254fr.Element).SetOne())
+	}
+	t := new(big.Int).Sub(bn254fr.Modulus(), big.NewInt(1))
+	t.Rsh(t, maxOrderRootBN254)
+	var w bn254fr.Element
+	w.Exp(nqr, t)
+	for i := maxOrderRootBN254; i > power; i-- {
+		w.Square(&w)
+	}
+	return w
+}
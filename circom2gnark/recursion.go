@@ -7,7 +7,9 @@ import (
 	"github.com/consensys/gnark/std/math/emulated"
 	recursion "github.com/consensys/gnark/std/recursion/groth16"
 
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
 	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
 )
 
@@ -118,4 +120,103 @@ func (circomProof *CircomProof) ToGnarkRecursionProofBN254(circomVk *CircomVerif
 	circomPublicSignals []string,
 ) (*GnarkRecursionProofBN254, error) {
 	return circomProof.ToGnarkRecursionBN254(circomVk, circomPublicSignals, true)
-}
\ No newline at end of file
+}
+
+// ToGnarkRecursionBLS12381 converts a Circom proof (BLS12-381) to the Gnark recursion proof format.
+func (circomProof *CircomProof) ToGnarkRecursionBLS12381(circomVk *CircomVerificationKey,
+	circomPublicSignals []string, fixedVk bool,
+) (*GnarkRecursionProofBLS12381, error) {
+	publicInputs, err := ConvertPublicInputsBLS12381(circomPublicSignals)
+	if err != nil {
+		return nil, err
+	}
+	gnarkProof, err := circomProof.ToGnarkBLS12381()
+	if err != nil {
+		return nil, err
+	}
+	recursionProof, err := recursion.ValueOfProof[sw_bls12381.G1Affine, sw_bls12381.G2Affine](gnarkProof)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert proof to recursion proof: %w", err)
+	}
+
+	gnarkVk, err := circomVk.ToGnarkBLS12381()
+	if err != nil {
+		return nil, err
+	}
+	publicInputElementsEmulated := make([]emulated.Element[sw_bls12381.ScalarField], len(publicInputs))
+	for i, input := range publicInputs {
+		bigIntValue := input.BigInt(new(big.Int))
+		publicInputElementsEmulated[i] = emulated.ValueOf[sw_bls12381.ScalarField](bigIntValue)
+	}
+	assignments := &GnarkRecursionProofBLS12381{
+		Proof: recursionProof,
+		PublicInputs: recursion.Witness[sw_bls12381.ScalarField]{
+			Public: publicInputElementsEmulated,
+		},
+	}
+	if !fixedVk {
+		recursionVk, err := recursion.ValueOfVerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl](gnarkVk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert verification key to recursion verification key: %w", err)
+		}
+		assignments.Vk = recursionVk
+	}
+	return assignments, nil
+}
+
+// PlaceholdersForRecursionBLS12381 creates placeholders for BLS12-381 recursion circuits.
+func PlaceholdersForRecursionBLS12381(circomVk *CircomVerificationKey,
+	nPublicInputs int, fixedVk bool,
+) (*GnarkRecursionPlaceholdersBLS12381, error) {
+	gnarkVk, err := circomVk.ToGnarkBLS12381()
+	if err != nil {
+		return nil, err
+	}
+	return createPlaceholdersForRecursionBLS12381(gnarkVk, nPublicInputs, fixedVk)
+}
+
+func createPlaceholdersForRecursionBLS12381(gnarkVk *groth16_bls12381.VerifyingKey,
+	nPublicInputs int, fixedVk bool,
+) (*GnarkRecursionPlaceholdersBLS12381, error) {
+	if gnarkVk == nil || nPublicInputs < 0 {
+		return nil, fmt.Errorf("invalid inputs to create placeholders for recursion")
+	}
+	placeholderVk, err := recursion.ValueOfVerifyingKeyFixed[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl](gnarkVk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert verification key to recursion verification key: %w", err)
+	}
+	placeholders := &GnarkRecursionPlaceholdersBLS12381{
+		Vk: placeholderVk,
+		Witness: recursion.Witness[sw_bls12381.ScalarField]{
+			Public: make([]emulated.Element[sw_bls12381.ScalarField], nPublicInputs),
+		},
+		Proof: recursion.Proof[sw_bls12381.G1Affine, sw_bls12381.G2Affine]{},
+	}
+	if !fixedVk {
+		placeholders.Vk.G1.K = make([]sw_bls12381.G1Affine, len(placeholders.Vk.G1.K))
+	}
+	return placeholders, nil
+}
+
+// ToGnarkProofBLS12381 converts to non-recursive Gnark proof over BLS12-381.
+func (circomProof *CircomProof) ToGnarkProofBLS12381(circomVk *CircomVerificationKey,
+	circomPublicSignals []string,
+) (*GnarkProofBLS12381, error) {
+	publicInputs, err := ConvertPublicInputsBLS12381(circomPublicSignals)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := circomProof.ToGnarkBLS12381()
+	if err != nil {
+		return nil, err
+	}
+	vk, err := circomVk.ToGnarkBLS12381()
+	if err != nil {
+		return nil, err
+	}
+	return &GnarkProofBLS12381{
+		Proof:        proof,
+		VerifyingKey: vk,
+		PublicInputs: publicInputs,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package circom2gnark
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/solidity"
+	"github.com/consensys/gnark/backend/witness"
+	"golang.org/x/crypto/sha3"
+)
+
+// SolidityCalldataBN254 holds the ABI arguments expected by the verifyProof
+// function of the Solidity verifiers exported by this package.
+type SolidityCalldataBN254 struct {
+	// Proof contains A, B and C in EIP-197 order: A.x, A.y, B.x.a1, B.x.a0,
+	// B.y.a1, B.y.a0, C.x, C.y.
+	Proof [8]*big.Int
+	// Commitments contains the x, y coordinates of every Pedersen commitment
+	// of the proof. It is empty for Circom proofs.
+	Commitments []*big.Int
+	// CommitmentPok is the batched proof of knowledge of the commitments. It
+	// is only encoded when Commitments is not empty.
+	CommitmentPok [2]*big.Int
+	// PublicInputs contains the public signals reduced modulo the scalar field.
+	PublicInputs []*big.Int
+}
+
+// ExportSolidityBN254 writes a Solidity Groth16 verifier contract for the
+// Circom verification key over BN254.
+func (circomVk *CircomVerificationKey) ExportSolidityBN254(w io.Writer, opts ...solidity.ExportOption) error {
+	gnarkVk, err := circomVk.ToGnarkBN254()
+	if err != nil {
+		return err
+	}
+	return ExportSolidityGnarkBN254(gnarkVk, w, opts...)
+}
+
+// ExportSolidityGnarkBN254 writes a Solidity Groth16 verifier contract for a
+// gnark BN254 verifying key, such as the one of a circuit aggregating Circom
+// proofs. If the circuit uses commitments, proofs must be generated with
+// solidity.WithProverTargetSolidityVerifier(backend.GROTH16).
+func ExportSolidityGnarkBN254(vk groth16.VerifyingKey, w io.Writer, opts ...solidity.ExportOption) error {
+	gnarkVk, ok := vk.(*groth16_bn254.VerifyingKey)
+	if !ok {
+		return fmt.Errorf("unsupported verifying key type %T, want BN254", vk)
+	}
+	if err := gnarkVk.ExportSolidity(w, opts...); err != nil {
+		return fmt.Errorf("failed to export solidity verifier: %w", err)
+	}
+	return nil
+}
+
+// ToSolidityCalldataBN254 converts a Circom proof (BN254) and its public
+// signals into the arguments of the exported verifier's verifyProof function.
+func (circomProof *CircomProof) ToSolidityCalldataBN254(circomPublicSignals []string) (*SolidityCalldataBN254, error) {
+	publicInputs, err := ConvertPublicInputsBN254(circomPublicSignals)
+	if err != nil {
+		return nil, err
+	}
+	gnarkProof, err := circomProof.ToGnarkBN254()
+	if err != nil {
+		return nil, err
+	}
+	return solidityCalldataBN254(gnarkProof, publicInputs), nil
+}
+
+// GnarkProofToSolidityCalldataBN254 converts a gnark BN254 Groth16 proof and
+// its public witness into the arguments of the verifyProof function exported
+// by ExportSolidityGnarkBN254. Emulated public inputs are encoded limb by
+// limb, in the same order as they appear in the public witness.
+func GnarkProofToSolidityCalldataBN254(proof groth16.Proof, publicWitness witness.Witness) (*SolidityCalldataBN254, error) {
+	gnarkProof, ok := proof.(*groth16_bn254.Proof)
+	if !ok {
+		return nil, fmt.Errorf("unsupported proof type %T, want BN254", proof)
+	}
+	publicInputs, ok := publicWitness.Vector().(bn254fr.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public witness type %T, want BN254", publicWitness.Vector())
+	}
+	return solidityCalldataBN254(gnarkProof, publicInputs), nil
+}
+
+// solidityCalldataBN254 lays out the proof points and public inputs as
+// uint256 words in the order the gnark Solidity verifier reads them.
+func solidityCalldataBN254(proof *groth16_bn254.Proof, publicInputs []bn254fr.Element) *SolidityCalldataBN254 {
+	calldata := &SolidityCalldataBN254{
+		Proof: [8]*big.Int{
+			proof.Ar.X.BigInt(new(big.Int)), proof.Ar.Y.BigInt(new(big.Int)),
+			proof.Bs.X.A1.BigInt(new(big.Int)), proof.Bs.X.A0.BigInt(new(big.Int)),
+			proof.Bs.Y.A1.BigInt(new(big.Int)), proof.Bs.Y.A0.BigInt(new(big.Int)),
+			proof.Krs.X.BigInt(new(big.Int)), proof.Krs.Y.BigInt(new(big.Int)),
+		},
+		PublicInputs: make([]*big.Int, len(publicInputs)),
+	}
+	if len(proof.Commitments) > 0 {
+		calldata.Commitments = g1Words(proof.Commitments...)
+		copy(calldata.CommitmentPok[:], g1Words(proof.CommitmentPok))
+	}
+	for i := range publicInputs {
+		calldata.PublicInputs[i] = publicInputs[i].BigInt(new(big.Int))
+	}
+	return calldata
+}
+
+// g1Words returns the x, y coordinates of the points as consecutive words.
+func g1Words(points ...bn254.G1Affine) []*big.Int {
+	words := make([]*big.Int, 0, 2*len(points))
+	for i := range points {
+		words = append(words, points[i].X.BigInt(new(big.Int)), points[i].Y.BigInt(new(big.Int)))
+	}
+	return words
+}
+
+// Signature returns the verifyProof function signature matching the number
+// of commitments and public inputs held.
+func (c *SolidityCalldataBN254) Signature() string {
+	args := []string{"uint256[8]"}
+	if len(c.Commitments) > 0 {
+		args = append(args, fmt.Sprintf("uint256[%d]", len(c.Commitments)), "uint256[2]")
+	}
+	args = append(args, fmt.Sprintf("uint256[%d]", len(c.PublicInputs)))
+	return "verifyProof(" + strings.Join(args, ",") + ")"
+}
+
+// Selector returns the 4-byte function selector of Signature.
+func (c *SolidityCalldataBN254) Selector() []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(c.Signature()))
+	return h.Sum(nil)[:4]
+}
+
+// Pack returns the ABI-encoded calldata, including the function selector,
+// for a verifyProof call. Every argument is a static array, so the encoding
+// is the selector followed by every element as a 32-byte big-endian word.
+func (c *SolidityCalldataBN254) Pack() ([]byte, error) {
+	words := append([]*big.Int{}, c.Proof[:]...)
+	if len(c.Commitments) > 0 {
+		words = append(words, c.Commitments...)
+		words = append(words, c.CommitmentPok[:]...)
+	}
+	words = append(words, c.PublicInputs...)
+
+	out := make([]byte, 0, 4+32*len(words))
+	out = append(out, c.Selector()...)
+	for i, w := range words {
+		word, err := abiWord(w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode calldata word %d: %v", i, err)
+		}
+		out = append(out, word...)
+	}
+	return out, nil
+}
+
+// abiWord encodes a non-negative integer as a 32-byte big-endian ABI word.
+func abiWord(v *big.Int) ([]byte, error) {
+	if v == nil || v.Sign() < 0 || v.BitLen() > 256 {
+		return nil, fmt.Errorf("value does not fit in uint256")
+	}
+	return leftPadBytes(v.Bytes(), 32), nil
+}
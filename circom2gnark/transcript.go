@@ -0,0 +1,42 @@
+package circom2gnark
+
+import (
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"golang.org/x/crypto/sha3"
+)
+
+// keccakTranscript replicates the Keccak256Transcript used by SnarkJS to
+// derive the PLONK and FFLONK challenges: G1 points are written as
+// uncompressed big-endian x || y, scalars as 32-byte big-endian values, and
+// the challenge is the Keccak-256 digest reduced modulo the scalar field.
+type keccakTranscript struct {
+	data []byte
+}
+
+// reset clears the transcript data, as SnarkJS does between rounds.
+func (t *keccakTranscript) reset() {
+	t.data = t.data[:0]
+}
+
+// addPolCommitment appends a G1 point to the transcript.
+func (t *keccakTranscript) addPolCommitment(p *bn254.G1Affine) {
+	x, y := p.X.Bytes(), p.Y.Bytes()
+	t.data = append(t.data, x[:]...)
+	t.data = append(t.data, y[:]...)
+}
+
+// addScalar appends a scalar field element to the transcript.
+func (t *keccakTranscript) addScalar(s *bn254fr.Element) {
+	b := s.Bytes()
+	t.data = append(t.data, b[:]...)
+}
+
+// getChallenge returns the challenge derived from the current data.
+func (t *keccakTranscript) getChallenge() bn254fr.Element {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(t.data)
+	var challenge bn254fr.Element
+	challenge.SetBytes(h.Sum(nil))
+	return challenge
+}
@@ -1,12 +1,28 @@
 package circom2gnark
 
 import (
+	bls12381fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
 	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
 	recursion "github.com/consensys/gnark/std/recursion/groth16"
 )
 
+// Curve names used by SnarkJS in the curve field of proofs and keys.
+const (
+	CurveBN254    = "bn128"
+	CurveBLS12381 = "bls12381"
+)
+
+// Proving systems used by SnarkJS in the protocol field of proofs and keys.
+const (
+	ProtocolGroth16 = "groth16"
+	ProtocolPlonk   = "plonk"
+	ProtocolFflonk  = "fflonk"
+)
+
 // CircomProof represents the proof structure output by SnarkJS.
 type CircomProof struct {
 	PiA      []string   `json:"pi_a"`
@@ -47,4 +63,25 @@ type GnarkProofBN254 struct {
 	Proof        *groth16_bn254.Proof
 	VerifyingKey *groth16_bn254.VerifyingKey
 	PublicInputs []bn254fr.Element
-}
\ No newline at end of file
+}
+
+// GnarkRecursionPlaceholdersBLS12381 holds placeholders for recursion over BLS12-381.
+type GnarkRecursionPlaceholdersBLS12381 struct {
+	Vk      recursion.VerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]
+	Witness recursion.Witness[sw_bls12381.ScalarField]
+	Proof   recursion.Proof[sw_bls12381.G1Affine, sw_bls12381.G2Affine]
+}
+
+// GnarkRecursionProofBLS12381 carries a BLS12-381 proof formatted for recursion.
+type GnarkRecursionProofBLS12381 struct {
+	Proof        recursion.Proof[sw_bls12381.G1Affine, sw_bls12381.G2Affine]
+	Vk           recursion.VerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl]
+	PublicInputs recursion.Witness[sw_bls12381.ScalarField]
+}
+
+// GnarkProofBLS12381 is a non-recursive proof over BLS12-381.
+type GnarkProofBLS12381 struct {
+	Proof        *groth16_bls12381.Proof
+	VerifyingKey *groth16_bls12381.VerifyingKey
+	PublicInputs []bls12381fr.Element
+}
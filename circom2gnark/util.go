@@ -6,7 +6,10 @@ import (
 	"math/big"
 	"strings"
 
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls12381fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fp "github.com/consensys/gnark-crypto/ecc/bn254/fp"
 )
 
 // stringToBigInt converts a decimal or hex string to a big.Int.
@@ -51,12 +54,9 @@ func stringToBytesWithSize(s string, size int) ([]byte, error) {
 	return b, nil
 }
 
-// stringToG1BN254 converts coordinates into a BN254 G1 point.
-func stringToG1BN254(h []string) (*bn254.G1Affine, error) {
-	if len(h) < 2 {
-		return nil, fmt.Errorf("not enough data for stringToG1BN254")
-	}
-	const coordBytes = 32
+// stringToG1Bytes converts G1 coordinates into uncompressed point bytes,
+// using coordBytes bytes per coordinate.
+func stringToG1Bytes(h []string, coordBytes int) ([]byte, error) {
 	hexa := len(h[0]) > 1 && strings.HasPrefix(h[0], "0x")
 	var b []byte
 	if hexa {
@@ -76,19 +76,12 @@ func stringToG1BN254(h []string) (*bn254.G1Affine, error) {
 			b = append(b, dec...)
 		}
 	}
-	p := new(bn254.G1Affine)
-	if err := p.Unmarshal(b); err != nil {
-		return nil, err
-	}
-	return p, nil
+	return b, nil
 }
 
-// stringToG2BN254 converts coordinates into a BN254 G2 point.
-func stringToG2BN254(h [][]string) (*bn254.G2Affine, error) {
-	if len(h) < 2 {
-		return nil, fmt.Errorf("not enough data for stringToG2BN254")
-	}
-	const coordBytes = 32
+// stringToG2Bytes converts G2 coordinates into uncompressed point bytes,
+// using coordBytes bytes per coordinate.
+func stringToG2Bytes(h [][]string, coordBytes int) ([]byte, error) {
 	hexa := len(h[0][0]) > 1 && strings.HasPrefix(h[0][0], "0x")
 	var b []byte
 	if hexa {
@@ -111,6 +104,34 @@ func stringToG2BN254(h [][]string) (*bn254.G2Affine, error) {
 			b = append(b, dec...)
 		}
 	}
+	return b, nil
+}
+
+// stringToG1BN254 converts coordinates into a BN254 G1 point.
+func stringToG1BN254(h []string) (*bn254.G1Affine, error) {
+	if len(h) < 2 {
+		return nil, fmt.Errorf("not enough data for stringToG1BN254")
+	}
+	b, err := stringToG1Bytes(h, bn254fp.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	p := new(bn254.G1Affine)
+	if err := p.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// stringToG2BN254 converts coordinates into a BN254 G2 point.
+func stringToG2BN254(h [][]string) (*bn254.G2Affine, error) {
+	if len(h) < 2 {
+		return nil, fmt.Errorf("not enough data for stringToG2BN254")
+	}
+	b, err := stringToG2Bytes(h, bn254fp.Bytes)
+	if err != nil {
+		return nil, err
+	}
 	p := new(bn254.G2Affine)
 	if err := p.Unmarshal(b); err != nil {
 		return nil, err
@@ -118,6 +139,38 @@ func stringToG2BN254(h [][]string) (*bn254.G2Affine, error) {
 	return p, nil
 }
 
+// stringToG1BLS12381 converts coordinates into a BLS12-381 G1 point.
+func stringToG1BLS12381(h []string) (*bls12381.G1Affine, error) {
+	if len(h) < 2 {
+		return nil, fmt.Errorf("not enough data for stringToG1BLS12381")
+	}
+	b, err := stringToG1Bytes(h, bls12381fp.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	p := new(bls12381.G1Affine)
+	if err := p.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// stringToG2BLS12381 converts coordinates into a BLS12-381 G2 point.
+func stringToG2BLS12381(h [][]string) (*bls12381.G2Affine, error) {
+	if len(h) < 2 {
+		return nil, fmt.Errorf("not enough data for stringToG2BLS12381")
+	}
+	b, err := stringToG2Bytes(h, bls12381fp.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	p := new(bls12381.G2Affine)
+	if err := p.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
 // leftPadBytes pads a byte slice to the desired length with leading zeros.
 func leftPadBytes(b []byte, size int) []byte {
 	if len(b) >= size {
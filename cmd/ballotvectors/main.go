@@ -0,0 +1,39 @@
+// Command ballotvectors regenerates the ballot proof fixture sets of the
+// testvectors package: for each set, the circuit inputs and the expected
+// cipherfields, vote ID, inputs hash and public signals.
+//
+//	go run ./cmd/ballotvectors -out artifacts/vectors [-set rating]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/vocdoni/davinci-circom/ballot/testvectors"
+)
+
+func main() {
+	out := flag.String("out", "artifacts/vectors", "directory to write the fixture files")
+	name := flag.String("set", "", "fixture set to regenerate (all if empty)")
+	flag.Parse()
+
+	sets := testvectors.Sets
+	if *name != "" {
+		set, err := testvectors.Lookup(*name)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sets = []testvectors.Set{set}
+	}
+	for _, set := range sets {
+		v, err := testvectors.Generate(set)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := v.Write(*out); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: inputs_hash %s\n", set.Name, v.Inputs.InputsHash)
+	}
+}
@@ -0,0 +1,131 @@
+package elgamal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// Ciphertext is an ElGamal ciphertext (C1, C2) with the points in TE
+// coordinates, as found in the cipherfields of a ballot. Ciphertexts are
+// additively homomorphic: adding two of them encrypts the sum of their
+// messages.
+type Ciphertext struct {
+	C1 [2]*big.Int
+	C2 [2]*big.Int
+}
+
+// NewCiphertext returns the ciphertext with the given TE points.
+func NewCiphertext(c1, c2 [2]*big.Int) *Ciphertext {
+	return &Ciphertext{C1: c1, C2: c2}
+}
+
+// NewZeroCiphertext returns the encryption of 0 with k = 0, whose points are
+// both the identity. It is the neutral element of Add.
+func NewZeroCiphertext() *Ciphertext {
+	return &Ciphertext{
+		C1: [2]*big.Int{big.NewInt(0), big.NewInt(1)},
+		C2: [2]*big.Int{big.NewInt(0), big.NewInt(1)},
+	}
+}
+
+// CiphertextFromRTE returns the ciphertext with the given RTE points.
+func CiphertextFromRTE(c1, c2 [2]*big.Int) *Ciphertext {
+	c1x, c1y := FromRTEtoTE(c1[0], c1[1])
+	c2x, c2y := FromRTEtoTE(c2[0], c2[1])
+	return NewCiphertext([2]*big.Int{c1x, c1y}, [2]*big.Int{c2x, c2y})
+}
+
+// RTE returns the points of the ciphertext in RTE coordinates.
+func (c *Ciphertext) RTE() (c1, c2 [2]*big.Int) {
+	c1[0], c1[1] = FromTEtoRTE(c.C1[0], c.C1[1])
+	c2[0], c2[1] = FromTEtoRTE(c.C2[0], c.C2[1])
+	return c1, c2
+}
+
+// Add returns the ciphertext of the sum of the messages of c and o.
+func (c *Ciphertext) Add(o *Ciphertext) (*Ciphertext, error) {
+	return c.combine(o, false)
+}
+
+// Sub returns the ciphertext of the difference of the messages of c and o,
+// such as a tally without a ballot that has been overwritten.
+func (c *Ciphertext) Sub(o *Ciphertext) (*Ciphertext, error) {
+	return c.combine(o, true)
+}
+
+// Mul returns the ciphertext of the message of c multiplied by scalar, such
+// as a vote multiplied by its weight.
+func (c *Ciphertext) Mul(scalar *big.Int) (*Ciphertext, error) {
+	if scalar == nil || scalar.Sign() < 0 {
+		return nil, fmt.Errorf("invalid scalar")
+	}
+	p1, p2, err := c.points()
+	if err != nil {
+		return nil, err
+	}
+	p1.ScalarMultiplication(p1, scalar)
+	p2.ScalarMultiplication(p2, scalar)
+	return newCiphertext(p1, p2), nil
+}
+
+// Equal reports whether c and o have the same points.
+func (c *Ciphertext) Equal(o *Ciphertext) bool {
+	for i := range 2 {
+		if c.C1[i] == nil || c.C2[i] == nil || o.C1[i] == nil || o.C2[i] == nil {
+			return false
+		}
+		if c.C1[i].Cmp(o.C1[i]) != 0 || c.C2[i].Cmp(o.C2[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Decrypt decrypts the ciphertext with the private key and the discrete log
+// table.
+func (c *Ciphertext) Decrypt(priv *big.Int, table *DLogTable) (uint64, error) {
+	return Decrypt(priv, c.C1, c.C2, table)
+}
+
+// combine adds o to c, or subtracts it if neg is set.
+func (c *Ciphertext) combine(o *Ciphertext, neg bool) (*Ciphertext, error) {
+	a1, a2, err := c.points()
+	if err != nil {
+		return nil, err
+	}
+	b1, b2, err := o.points()
+	if err != nil {
+		return nil, err
+	}
+	if neg {
+		b1.Neg(b1)
+		b2.Neg(b2)
+	}
+	a1.Add(a1, b1)
+	a2.Add(a2, b2)
+	return newCiphertext(a1, a2), nil
+}
+
+// points returns the RTE points of the ciphertext, checking that they lie on
+// the curve.
+func (c *Ciphertext) points() (p1, p2 *twistededwards.PointAffine, err error) {
+	if c == nil || c.C1[0] == nil || c.C1[1] == nil || c.C2[0] == nil || c.C2[1] == nil {
+		return nil, nil, fmt.Errorf("missing ciphertext point")
+	}
+	if p1, err = fromTE(c.C1[0], c.C1[1]); err != nil {
+		return nil, nil, fmt.Errorf("invalid C1: %w", err)
+	}
+	if p2, err = fromTE(c.C2[0], c.C2[1]); err != nil {
+		return nil, nil, fmt.Errorf("invalid C2: %w", err)
+	}
+	return p1, p2, nil
+}
+
+// newCiphertext returns the ciphertext of the given RTE points.
+func newCiphertext(p1, p2 *twistededwards.PointAffine) *Ciphertext {
+	c1x, c1y := toTE(p1)
+	c2x, c2y := toTE(p2)
+	return NewCiphertext([2]*big.Int{c1x, c1y}, [2]*big.Int{c2x, c2y})
+}
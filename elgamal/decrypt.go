@@ -0,0 +1,95 @@
+package elgamal
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// DecryptPoint returns the message point M = C2 - priv·C1 of a ciphertext,
+// with all the points in TE coordinates.
+func DecryptPoint(priv *big.Int, c1, c2 [2]*big.Int) ([2]*big.Int, error) {
+	m, err := decryptPoint(priv, c1, c2)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	x, y := toTE(m)
+	return [2]*big.Int{x, y}, nil
+}
+
+func decryptPoint(priv *big.Int, c1, c2 [2]*big.Int) (*twistededwards.PointAffine, error) {
+	if priv == nil || priv.Sign() < 0 {
+		return nil, fmt.Errorf("invalid private key")
+	}
+	p1, p2, err := NewCiphertext(c1, c2).points()
+	if err != nil {
+		return nil, err
+	}
+	var s twistededwards.PointAffine
+	s.ScalarMultiplication(p1, priv)
+	s.Neg(&s)
+	return s.Add(p2, &s), nil
+}
+
+// Decrypt decrypts a ciphertext with the private key, recovering the message
+// from M = m·G with the discrete log table.
+func Decrypt(priv *big.Int, c1, c2 [2]*big.Int, table *DLogTable) (uint64, error) {
+	m, err := decryptPoint(priv, c1, c2)
+	if err != nil {
+		return 0, err
+	}
+	return table.dlog(m)
+}
+
+// DecryptWithProof decrypts the ciphertext as Decrypt does and proves that
+// the message is correct: the proof shows that log_G(Pub) = log_C1(C2 - M)
+// with M = m·G, so anyone holding the public key can check the result with
+// VerifyDecryption. The proof nonce is read from rand; if rand is nil,
+// crypto/rand is used.
+func (c *Ciphertext) DecryptWithProof(priv *big.Int, table *DLogTable, rand io.Reader) (uint64, *DLEQProof, error) {
+	m, err := c.Decrypt(priv, table)
+	if err != nil {
+		return 0, nil, err
+	}
+	c1, _, _ := c.points()
+	curve := twistededwards.GetEdwardsCurve()
+	var pub, s twistededwards.PointAffine
+	pub.ScalarMultiplication(&curve.Base, priv)
+	s.ScalarMultiplication(c1, priv)
+	proof, err := proveDLEQ(&curve.Base, c1, &pub, &s, priv, rand)
+	if err != nil {
+		return 0, nil, err
+	}
+	return m, proof, nil
+}
+
+// VerifyDecryption checks that message is the decryption of the ciphertext
+// under the public key (in TE coordinates) with a proof returned by
+// DecryptWithProof.
+func VerifyDecryption(pubX, pubY *big.Int, c *Ciphertext, message uint64, proof *DLEQProof) error {
+	pub, err := publicKey(pubX, pubY)
+	if err != nil {
+		return err
+	}
+	c1, c2, err := c.points()
+	if err != nil {
+		return err
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	if err := proof.verifyDLEQ(&curve.Base, c1, pub, decryptionShared(c2, message)); err != nil {
+		return fmt.Errorf("invalid decryption: %w", err)
+	}
+	return nil
+}
+
+// decryptionShared returns the shared secret C2 - m·G of a ciphertext
+// decrypting to m.
+func decryptionShared(c2 *twistededwards.PointAffine, m uint64) *twistededwards.PointAffine {
+	curve := twistededwards.GetEdwardsCurve()
+	var s twistededwards.PointAffine
+	s.ScalarMultiplication(&curve.Base, new(big.Int).SetUint64(m))
+	s.Neg(&s)
+	return s.Add(c2, &s)
+}
@@ -0,0 +1,94 @@
+package elgamal
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/iden3/go-iden3-crypto/poseidon"
+)
+
+// DLEQProof is a non-interactive Chaum–Pedersen proof that two points P1 and
+// P2 share the same discrete logarithm x over two bases, P1 = x·B1 and
+// P2 = x·B2. The commitments A = w·B1 and B = w·B2 are in TE coordinates,
+// the challenge e is the Poseidon hash of the TE coordinates of B1, B2, P1,
+// P2, A and B reduced modulo the subgroup order, and Z = w + e·x.
+type DLEQProof struct {
+	A [2]*big.Int `json:"a"`
+	B [2]*big.Int `json:"b"`
+	Z *big.Int    `json:"z"`
+}
+
+// proveDLEQ proves that p1 = x·b1 and p2 = x·b2, reading the commitment
+// nonce from rand.
+func proveDLEQ(b1, b2, p1, p2 *twistededwards.PointAffine, x *big.Int, rand io.Reader) (*DLEQProof, error) {
+	curve := twistededwards.GetEdwardsCurve()
+	w, err := randomScalar(rand, &curve.Order)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate proof nonce: %w", err)
+	}
+	var a, b twistededwards.PointAffine
+	a.ScalarMultiplication(b1, w)
+	b.ScalarMultiplication(b2, w)
+	e, err := dleqChallenge(b1, b2, p1, p2, &a, &b)
+	if err != nil {
+		return nil, err
+	}
+	z := new(big.Int).Mul(e, x)
+	z.Add(z, w).Mod(z, &curve.Order)
+
+	proof := &DLEQProof{Z: z}
+	proof.A[0], proof.A[1] = toTE(&a)
+	proof.B[0], proof.B[1] = toTE(&b)
+	return proof, nil
+}
+
+// verifyDLEQ checks that the proof shows p1 = x·b1 and p2 = x·b2 for some x,
+// that is Z·B1 = A + e·P1 and Z·B2 = B + e·P2.
+func (p *DLEQProof) verifyDLEQ(b1, b2, p1, p2 *twistededwards.PointAffine) error {
+	if p == nil || p.Z == nil || p.A[0] == nil || p.A[1] == nil || p.B[0] == nil || p.B[1] == nil {
+		return fmt.Errorf("missing proof value")
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	if p.Z.Sign() < 0 || p.Z.Cmp(&curve.Order) >= 0 {
+		return fmt.Errorf("invalid proof response")
+	}
+	a, err := fromTE(p.A[0], p.A[1])
+	if err != nil {
+		return fmt.Errorf("invalid proof commitment: %w", err)
+	}
+	b, err := fromTE(p.B[0], p.B[1])
+	if err != nil {
+		return fmt.Errorf("invalid proof commitment: %w", err)
+	}
+	e, err := dleqChallenge(b1, b2, p1, p2, a, b)
+	if err != nil {
+		return err
+	}
+	for _, eq := range [][3]*twistededwards.PointAffine{{b1, a, p1}, {b2, b, p2}} {
+		var lhs, rhs twistededwards.PointAffine
+		lhs.ScalarMultiplication(eq[0], p.Z)
+		rhs.ScalarMultiplication(eq[2], e)
+		rhs.Add(&rhs, eq[1])
+		if !lhs.Equal(&rhs) {
+			return fmt.Errorf("invalid proof")
+		}
+	}
+	return nil
+}
+
+// dleqChallenge returns the Fiat–Shamir challenge of a DLEQ proof.
+func dleqChallenge(points ...*twistededwards.PointAffine) (*big.Int, error) {
+	inputs := make([]*big.Int, 0, 2*len(points))
+	for _, p := range points {
+		x, y := toTE(p)
+		inputs = append(inputs, x, y)
+	}
+	h, err := poseidon.Hash(inputs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute proof challenge: %w", err)
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	return h.Mod(h, &curve.Order), nil
+}
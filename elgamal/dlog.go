@@ -0,0 +1,149 @@
+package elgamal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// MsgBits is the size of the messages encrypted by the ElGamal template of
+// the ballot circuits (msg_bits).
+const MsgBits = 32
+
+// MaxDLogBits is the largest message size supported by a DLogTable.
+const MaxDLogBits = 48
+
+// dlogTableVersion is the version of the DLogTable binary encoding.
+const dlogTableVersion = 1
+
+// DLogTable is a baby-step giant-step table that recovers messages m < 2^bits
+// from their point m·G. It stores the baby steps j·G for j < 2^ceil(bits/2),
+// indexed by the low 64 bits of their x coordinate, and can be serialised to
+// avoid rebuilding it.
+type DLogTable struct {
+	bits, babyBits uint
+	keys           []uint64
+	steps          []uint32
+}
+
+// NewDLogTable builds the table for messages of the given size, usually
+// MsgBits.
+func NewDLogTable(bits int) (*DLogTable, error) {
+	if bits < 1 || bits > MaxDLogBits {
+		return nil, fmt.Errorf("invalid message size: got %d bits, want 1 to %d", bits, MaxDLogBits)
+	}
+	t := &DLogTable{bits: uint(bits), babyBits: uint(bits+1) / 2}
+	n := 1 << t.babyBits
+	keys := make([]uint64, n)
+	curve := twistededwards.GetEdwardsCurve()
+	var p twistededwards.PointAffine
+	p.X.SetZero()
+	p.Y.SetOne()
+	for j := range n {
+		keys[j] = dlogKey(&p)
+		p.Add(&p, &curve.Base)
+	}
+	t.steps = make([]uint32, n)
+	for j := range t.steps {
+		t.steps[j] = uint32(j)
+	}
+	sort.Slice(t.steps, func(a, b int) bool { return keys[t.steps[a]] < keys[t.steps[b]] })
+	t.keys = make([]uint64, n)
+	for i, j := range t.steps {
+		t.keys[i] = keys[j]
+	}
+	return t, nil
+}
+
+// Bits returns the message size of the table.
+func (t *DLogTable) Bits() int {
+	return int(t.bits)
+}
+
+// dlogKey indexes the table points by the low 64 bits of their x coordinate.
+func dlogKey(p *twistededwards.PointAffine) uint64 {
+	return p.X.Bits()[0]
+}
+
+// DLog returns m < 2^bits such that (x, y) = m·G, with the point in TE
+// coordinates.
+func (t *DLogTable) DLog(x, y *big.Int) (uint64, error) {
+	p, err := fromTE(x, y)
+	if err != nil {
+		return 0, err
+	}
+	return t.dlog(p)
+}
+
+func (t *DLogTable) dlog(p *twistededwards.PointAffine) (uint64, error) {
+	if t == nil {
+		return 0, fmt.Errorf("missing discrete log table")
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	// giant step: -2^babyBits·G
+	var giant, q, check twistededwards.PointAffine
+	giant.ScalarMultiplication(&curve.Base, new(big.Int).Lsh(big.NewInt(1), t.babyBits))
+	giant.Neg(&giant)
+	q.Set(p)
+	for i := uint64(0); i < 1<<(t.bits-t.babyBits); i++ {
+		key := dlogKey(&q)
+		for k := sort.Search(len(t.keys), func(k int) bool { return t.keys[k] >= key }); k < len(t.keys) && t.keys[k] == key; k++ {
+			// the key is only part of the coordinate, so candidates are
+			// checked against the point
+			m := i<<t.babyBits + uint64(t.steps[k])
+			check.ScalarMultiplication(&curve.Base, new(big.Int).SetUint64(m))
+			if check.Equal(p) {
+				return m, nil
+			}
+		}
+		q.Add(&q, &giant)
+	}
+	return 0, fmt.Errorf("message out of range: not below 2^%d", t.bits)
+}
+
+// MarshalBinary encodes the table as a version byte, the message size, the
+// number of baby steps, and the sorted entries (8-byte key and 4-byte step),
+// all big-endian.
+func (t *DLogTable) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 6, 6+12*len(t.keys))
+	out[0], out[1] = dlogTableVersion, byte(t.bits)
+	binary.BigEndian.PutUint32(out[2:], uint32(len(t.keys)))
+	for i := range t.keys {
+		out = binary.BigEndian.AppendUint64(out, t.keys[i])
+		out = binary.BigEndian.AppendUint32(out, t.steps[i])
+	}
+	return out, nil
+}
+
+// UnmarshalBinary decodes a table encoded by MarshalBinary.
+func (t *DLogTable) UnmarshalBinary(data []byte) error {
+	if len(data) < 6 {
+		return fmt.Errorf("invalid discrete log table: too short")
+	}
+	if data[0] != dlogTableVersion {
+		return fmt.Errorf("unsupported discrete log table version: %d", data[0])
+	}
+	bits := uint(data[1])
+	if bits < 1 || bits > MaxDLogBits {
+		return fmt.Errorf("invalid discrete log table message size: %d bits", bits)
+	}
+	babyBits := (bits + 1) / 2
+	n := binary.BigEndian.Uint32(data[2:])
+	if n != 1<<babyBits || len(data) != 6+12*int(n) {
+		return fmt.Errorf("invalid discrete log table size: %d entries in %d bytes", n, len(data))
+	}
+	keys, steps := make([]uint64, n), make([]uint32, n)
+	for i := range keys {
+		entry := data[6+12*i:]
+		keys[i] = binary.BigEndian.Uint64(entry)
+		steps[i] = binary.BigEndian.Uint32(entry[8:])
+		if i > 0 && keys[i] < keys[i-1] {
+			return fmt.Errorf("invalid discrete log table: entries not sorted")
+		}
+	}
+	*t = DLogTable{bits: bits, babyBits: babyBits, keys: keys, steps: steps}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Package elgamal implements the ElGamal encryption over BabyJubJub used by
+// the ballot circuits. Points are exchanged in the Twisted Edwards form of
+// circom and circomlibjs (TE), while the arithmetic runs on the Reduced
+// Twisted Edwards form of gnark (RTE).
+package elgamal
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// scalingFactor used to transform between BabyJubJub forms.
+// See davinci-node/crypto/ecc/format/twistededwards.go
+var scalingFactor, _ = new(big.Int).SetString("6360561867910373094066688120553762416144456282423235903351243436111059670888", 10)
+
+// FromRTEtoTE converts a point from Reduced TwistedEdwards (Gnark) to TwistedEdwards (Circom/Iden3) coordinates.
+// It applies the transformation:
+//
+//	x = x'/(-f)
+//	y = y'
+func FromRTEtoTE(x, y *big.Int) (*big.Int, *big.Int) {
+	var negF, xTE fr.Element
+	negF.SetBigInt(scalingFactor).Neg(&negF).Inverse(&negF)
+	xTE.SetBigInt(x).Mul(&xTE, &negF)
+	return xTE.BigInt(new(big.Int)), new(big.Int).Set(y)
+}
+
+// FromTEtoRTE converts a point from TwistedEdwards (Circom/Iden3) to Reduced TwistedEdwards (Gnark) coordinates.
+// It applies the transformation:
+//
+//	x' = x*(-f)
+//	y' = y
+func FromTEtoRTE(x, y *big.Int) (*big.Int, *big.Int) {
+	var negF, xRTE fr.Element
+	negF.SetBigInt(scalingFactor).Neg(&negF)
+	xRTE.SetBigInt(x).Mul(&xRTE, &negF)
+	return xRTE.BigInt(new(big.Int)), new(big.Int).Set(y)
+}
+
+// GenerateKey returns a private key in the BabyJubJub subgroup, read from
+// rand, and its public key in TE coordinates. If rand is nil, crypto/rand is
+// used.
+func GenerateKey(rand io.Reader) (priv, pubX, pubY *big.Int, err error) {
+	curve := twistededwards.GetEdwardsCurve()
+	priv, err = randomScalar(rand, &curve.Order)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	var pub twistededwards.PointAffine
+	pub.ScalarMultiplication(&curve.Base, priv)
+	pubX, pubY = toTE(&pub)
+	return priv, pubX, pubY, nil
+}
+
+// randomScalar returns a uniform non-zero scalar below order.
+func randomScalar(r io.Reader, order *big.Int) (*big.Int, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	for {
+		s, err := rand.Int(r, order)
+		if err != nil {
+			return nil, err
+		}
+		if s.Sign() != 0 {
+			return s, nil
+		}
+	}
+}
+
+// Encrypt encrypts message with the public key (in TE coordinates) and the
+// randomness k, returning the ciphertext points C1 = k·G and
+// C2 = message·G + k·Pub in TE coordinates, as computed by the ElGamal
+// template of the ballot circuits.
+func Encrypt(message, pubX, pubY, k *big.Int) (c1, c2 [2]*big.Int, err error) {
+	if message == nil || pubX == nil || pubY == nil || k == nil {
+		return c1, c2, fmt.Errorf("missing encryption input")
+	}
+	if message.Sign() < 0 || k.Sign() < 0 {
+		return c1, c2, fmt.Errorf("negative message or randomness")
+	}
+	pub, err := publicKey(pubX, pubY)
+	if err != nil {
+		return c1, c2, err
+	}
+	curve := twistededwards.GetEdwardsCurve()
+
+	// C1 = k·G, C2 = M + S with M = message·G and S = k·Pub
+	var p1, s, m, p2 twistededwards.PointAffine
+	p1.ScalarMultiplication(&curve.Base, k)
+	s.ScalarMultiplication(pub, k)
+	m.ScalarMultiplication(&curve.Base, message)
+	p2.Add(&m, &s)
+
+	c1[0], c1[1] = toTE(&p1)
+	c2[0], c2[1] = toTE(&p2)
+	return c1, c2, nil
+}
+
+// fromTE returns the RTE point of the given TE coordinates, checking that it
+// lies on the curve.
+func fromTE(x, y *big.Int) (*twistededwards.PointAffine, error) {
+	xRTE, yRTE := FromTEtoRTE(x, y)
+	p := &twistededwards.PointAffine{}
+	p.X.SetBigInt(xRTE)
+	p.Y.SetBigInt(yRTE)
+	if !p.IsOnCurve() {
+		return nil, fmt.Errorf("point is not on the BabyJubJub curve")
+	}
+	return p, nil
+}
+
+// toTE returns the TE coordinates of an RTE point.
+func toTE(p *twistededwards.PointAffine) (*big.Int, *big.Int) {
+	return FromRTEtoTE(p.X.BigInt(new(big.Int)), p.Y.BigInt(new(big.Int)))
+}
@@ -0,0 +1,128 @@
+package elgamal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// PackedPointSize is the size of a packed point.
+const PackedPointSize = 32
+
+// teA and teD are the coefficients of BabyJubJub in TE coordinates,
+// a·x^2 + y^2 = 1 + d·x^2·y^2.
+var teA, teD = fr.NewElement(168700), fr.NewElement(168696)
+
+// halfModulus is (p-1)/2, above which x coordinates are negative for the
+// packing sign bit.
+var halfModulus = new(big.Int).Rsh(fr.Modulus(), 1)
+
+// Pack packs the point into 32 bytes as packPoint of circomlibjs does: y in
+// little-endian with the top bit set if x > (p-1)/2.
+func (p *PointTE) Pack() ([PackedPointSize]byte, error) {
+	var out [PackedPointSize]byte
+	if p == nil || p.X == nil || p.Y == nil {
+		return out, fmt.Errorf("missing point coordinate")
+	}
+	if err := validateRange(p.X, p.Y); err != nil {
+		return out, err
+	}
+	p.Y.FillBytes(out[:])
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	if p.X.Cmp(halfModulus) > 0 {
+		out[PackedPointSize-1] |= 0x80
+	}
+	return out, nil
+}
+
+// UnpackPointTE unpacks a point packed by Pack or by packPoint of
+// circomlibjs. The point is checked to be on the curve but not in the
+// subgroup, which Validate does.
+func UnpackPointTE(packed []byte) (*PointTE, error) {
+	if len(packed) != PackedPointSize {
+		return nil, fmt.Errorf("invalid packed point size: got %d, want %d", len(packed), PackedPointSize)
+	}
+	buf := make([]byte, PackedPointSize)
+	for i := range buf {
+		buf[i] = packed[PackedPointSize-1-i]
+	}
+	sign := buf[0]&0x80 != 0
+	buf[0] &= 0x7f
+	y := new(big.Int).SetBytes(buf)
+	if y.Cmp(fr.Modulus()) >= 0 {
+		return nil, fmt.Errorf("invalid packed point: y out of field range")
+	}
+
+	// x^2 = (1 - y^2) / (a - d·y^2)
+	var fy, y2, num, den, x2, fx fr.Element
+	fy.SetBigInt(y)
+	y2.Square(&fy)
+	num.SetOne().Sub(&num, &y2)
+	den.Mul(&teD, &y2)
+	den.Sub(&teA, &den)
+	if den.IsZero() {
+		return nil, fmt.Errorf("invalid packed point: not on the BabyJubJub curve")
+	}
+	x2.Div(&num, &den)
+	if fx.Sqrt(&x2) == nil {
+		return nil, fmt.Errorf("invalid packed point: not on the BabyJubJub curve")
+	}
+	x := fx.BigInt(new(big.Int))
+	if x.Cmp(halfModulus) > 0 {
+		x.Sub(fr.Modulus(), x)
+	}
+	if sign {
+		if x.Sign() == 0 {
+			return nil, fmt.Errorf("invalid packed point: sign set for x = 0")
+		}
+		x.Sub(fr.Modulus(), x)
+	}
+	return &PointTE{X: x, Y: y}, nil
+}
+
+// PackPoint packs a point in TE coordinates, as PointTE.Pack does.
+func PackPoint(x, y *big.Int) ([PackedPointSize]byte, error) {
+	return (&PointTE{X: x, Y: y}).Pack()
+}
+
+// UnpackPoint unpacks a point to TE coordinates, as UnpackPointTE does.
+func UnpackPoint(packed []byte) (x, y *big.Int, err error) {
+	p, err := UnpackPointTE(packed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p.X, p.Y, nil
+}
+
+// Pack packs the ciphertext into 64 bytes, the packed C1 followed by the
+// packed C2.
+func (c *Ciphertext) Pack() ([2 * PackedPointSize]byte, error) {
+	var out [2 * PackedPointSize]byte
+	for i, p := range [][2]*big.Int{c.C1, c.C2} {
+		packed, err := PackPoint(p[0], p[1])
+		if err != nil {
+			return out, fmt.Errorf("invalid C%d: %w", i+1, err)
+		}
+		copy(out[i*PackedPointSize:], packed[:])
+	}
+	return out, nil
+}
+
+// UnpackCiphertext unpacks a ciphertext packed by Ciphertext.Pack.
+func UnpackCiphertext(packed []byte) (*Ciphertext, error) {
+	if len(packed) != 2*PackedPointSize {
+		return nil, fmt.Errorf("invalid packed ciphertext size: got %d, want %d", len(packed), 2*PackedPointSize)
+	}
+	c1, err := UnpackPointTE(packed[:PackedPointSize])
+	if err != nil {
+		return nil, fmt.Errorf("invalid C1: %w", err)
+	}
+	c2, err := UnpackPointTE(packed[PackedPointSize:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid C2: %w", err)
+	}
+	return NewCiphertext(c1.Coordinates(), c2.Coordinates()), nil
+}
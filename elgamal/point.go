@@ -0,0 +1,274 @@
+package elgamal
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// PointTE is a BabyJubJub point in TE (circom) coordinates. It is encoded
+// in JSON as circom does, an array of two decimal strings.
+type PointTE struct {
+	X, Y *big.Int
+}
+
+// PointRTE is a BabyJubJub point in RTE (gnark) coordinates, as served by
+// the sequencer. It is encoded in JSON as an array of two decimal strings.
+type PointRTE struct {
+	X, Y *big.Int
+}
+
+// ParsePointTE parses the coordinates of a TE point, given as decimal or
+// 0x-prefixed hex strings, and validates it.
+func ParsePointTE(x, y string) (*PointTE, error) {
+	px, py, err := parseCoordinates(x, y)
+	if err != nil {
+		return nil, err
+	}
+	p := &PointTE{X: px, Y: py}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParsePointRTE parses the coordinates of an RTE point, given as decimal or
+// 0x-prefixed hex strings, and validates it.
+func ParsePointRTE(x, y string) (*PointRTE, error) {
+	px, py, err := parseCoordinates(x, y)
+	if err != nil {
+		return nil, err
+	}
+	p := &PointRTE{X: px, Y: py}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// RTE returns the point in RTE coordinates.
+func (p *PointTE) RTE() *PointRTE {
+	x, y := FromTEtoRTE(p.X, p.Y)
+	return &PointRTE{X: x, Y: y}
+}
+
+// TE returns the point in TE coordinates.
+func (p *PointRTE) TE() *PointTE {
+	x, y := FromRTEtoTE(p.X, p.Y)
+	return &PointTE{X: x, Y: y}
+}
+
+// Coordinates returns the coordinates of the point, as used by Encrypt and
+// the ballot inputs.
+func (p *PointTE) Coordinates() [2]*big.Int {
+	return [2]*big.Int{p.X, p.Y}
+}
+
+// Validate checks that the point can be used as an ElGamal public key or
+// ciphertext point in the ballot circuits: its coordinates are canonical
+// field elements, it lies on the curve and in the prime-order subgroup, and
+// it is not the identity, which the ElGamal template rejects.
+func (p *PointTE) Validate() error {
+	if p == nil || p.X == nil || p.Y == nil {
+		return fmt.Errorf("missing point coordinate")
+	}
+	if err := validateRange(p.X, p.Y); err != nil {
+		return err
+	}
+	return validatePoint(p.RTE())
+}
+
+// Validate checks the point as PointTE.Validate does.
+func (p *PointRTE) Validate() error {
+	if p == nil || p.X == nil || p.Y == nil {
+		return fmt.Errorf("missing point coordinate")
+	}
+	if err := validateRange(p.X, p.Y); err != nil {
+		return err
+	}
+	return validatePoint(p)
+}
+
+// IsOnCurve reports whether the point lies on BabyJubJub.
+func (p *PointTE) IsOnCurve() bool {
+	return p.RTE().IsOnCurve()
+}
+
+// IsOnCurve reports whether the point lies on BabyJubJub.
+func (p *PointRTE) IsOnCurve() bool {
+	return p.affine().IsOnCurve()
+}
+
+// IsIdentity reports whether the point is the identity (0, 1).
+func (p *PointTE) IsIdentity() bool {
+	return p.X.Sign() == 0 && p.Y.Cmp(big.NewInt(1)) == 0
+}
+
+// IsIdentity reports whether the point is the identity (0, 1).
+func (p *PointRTE) IsIdentity() bool {
+	return p.X.Sign() == 0 && p.Y.Cmp(big.NewInt(1)) == 0
+}
+
+// InSubgroup reports whether the point lies in the prime-order subgroup
+// generated by the base point, that is order·P is the identity.
+func (p *PointTE) InSubgroup() bool {
+	return p.RTE().InSubgroup()
+}
+
+// InSubgroup reports whether the point lies in the prime-order subgroup
+// generated by the base point, that is order·P is the identity.
+func (p *PointRTE) InSubgroup() bool {
+	a := p.affine()
+	if !a.IsOnCurve() {
+		return false
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	a.ScalarMultiplication(a, &curve.Order)
+	return a.IsZero()
+}
+
+// Equal reports whether p and o are the same point.
+func (p *PointTE) Equal(o *PointTE) bool {
+	return p.X.Cmp(o.X) == 0 && p.Y.Cmp(o.Y) == 0
+}
+
+// Equal reports whether p and o are the same point.
+func (p *PointRTE) Equal(o *PointRTE) bool {
+	return p.X.Cmp(o.X) == 0 && p.Y.Cmp(o.Y) == 0
+}
+
+// String returns the point as (x, y) in decimal.
+func (p *PointTE) String() string {
+	return fmt.Sprintf("(%s, %s)", p.X, p.Y)
+}
+
+// String returns the point as (x, y) in decimal.
+func (p *PointRTE) String() string {
+	return fmt.Sprintf("(%s, %s)", p.X, p.Y)
+}
+
+// MarshalJSON encodes the point as an array of two decimal strings.
+func (p *PointTE) MarshalJSON() ([]byte, error) {
+	return marshalPoint(p.X, p.Y)
+}
+
+// UnmarshalJSON decodes and validates a point encoded by MarshalJSON.
+func (p *PointTE) UnmarshalJSON(data []byte) error {
+	x, y, err := unmarshalPoint(data)
+	if err != nil {
+		return err
+	}
+	parsed := &PointTE{X: x, Y: y}
+	if err := parsed.Validate(); err != nil {
+		return err
+	}
+	*p = *parsed
+	return nil
+}
+
+// MarshalJSON encodes the point as an array of two decimal strings.
+func (p *PointRTE) MarshalJSON() ([]byte, error) {
+	return marshalPoint(p.X, p.Y)
+}
+
+// UnmarshalJSON decodes and validates a point encoded by MarshalJSON.
+func (p *PointRTE) UnmarshalJSON(data []byte) error {
+	x, y, err := unmarshalPoint(data)
+	if err != nil {
+		return err
+	}
+	parsed := &PointRTE{X: x, Y: y}
+	if err := parsed.Validate(); err != nil {
+		return err
+	}
+	*p = *parsed
+	return nil
+}
+
+// ValidatePublicKey checks an ElGamal public key in TE coordinates, as
+// PointTE.Validate does.
+func ValidatePublicKey(x, y *big.Int) error {
+	if err := (&PointTE{X: x, Y: y}).Validate(); err != nil {
+		return fmt.Errorf("invalid public key: %w", err)
+	}
+	return nil
+}
+
+// affine returns the gnark point with the RTE coordinates, reduced modulo
+// the field.
+func (p *PointRTE) affine() *twistededwards.PointAffine {
+	a := &twistededwards.PointAffine{}
+	a.X.SetBigInt(p.X)
+	a.Y.SetBigInt(p.Y)
+	return a
+}
+
+// validateRange checks that the coordinates are canonical field elements.
+func validateRange(coords ...*big.Int) error {
+	for _, c := range coords {
+		if c.Sign() < 0 || c.Cmp(fr.Modulus()) >= 0 {
+			return fmt.Errorf("point coordinate out of field range")
+		}
+	}
+	return nil
+}
+
+// validatePoint checks that a point with canonical coordinates is a valid
+// subgroup point other than the identity.
+func validatePoint(p *PointRTE) error {
+	if !p.IsOnCurve() {
+		return fmt.Errorf("point is not on the BabyJubJub curve")
+	}
+	if p.IsIdentity() {
+		return fmt.Errorf("identity point")
+	}
+	if !p.InSubgroup() {
+		return fmt.Errorf("point is not in the prime-order subgroup")
+	}
+	return nil
+}
+
+// parseCoordinates parses two decimal or 0x-prefixed hex coordinates.
+func parseCoordinates(x, y string) (*big.Int, *big.Int, error) {
+	px, err := parseCoordinate(x)
+	if err != nil {
+		return nil, nil, err
+	}
+	py, err := parseCoordinate(y)
+	if err != nil {
+		return nil, nil, err
+	}
+	return px, py, nil
+}
+
+func parseCoordinate(s string) (*big.Int, error) {
+	base := 10
+	digits := s
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		base, digits = 16, s[2:]
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok || digits == "" || strings.ContainsAny(digits, "+-_") {
+		return nil, fmt.Errorf("invalid point coordinate: %q", s)
+	}
+	return n, nil
+}
+
+func marshalPoint(x, y *big.Int) ([]byte, error) {
+	if x == nil || y == nil {
+		return nil, fmt.Errorf("missing point coordinate")
+	}
+	return json.Marshal([2]string{x.String(), y.String()})
+}
+
+func unmarshalPoint(data []byte) (*big.Int, *big.Int, error) {
+	var coords [2]string
+	if err := json.Unmarshal(data, &coords); err != nil {
+		return nil, nil, fmt.Errorf("invalid point: %w", err)
+	}
+	return parseCoordinates(coords[0], coords[1])
+}
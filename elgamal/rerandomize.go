@@ -0,0 +1,92 @@
+package elgamal
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// Rerandomize re-encrypts the ciphertext under the public key (in TE
+// coordinates) with the randomness r, returning (C1 + r·G, C2 + r·Pub). The
+// result encrypts the same message but cannot be linked to c without r.
+func (c *Ciphertext) Rerandomize(pubX, pubY, r *big.Int) (*Ciphertext, error) {
+	if r == nil || r.Sign() < 0 {
+		return nil, fmt.Errorf("invalid randomness")
+	}
+	pub, err := publicKey(pubX, pubY)
+	if err != nil {
+		return nil, err
+	}
+	c1, c2, err := c.points()
+	if err != nil {
+		return nil, err
+	}
+	d1, d2 := rerandomization(pub, r)
+	c1.Add(c1, d1)
+	c2.Add(c2, d2)
+	return newCiphertext(c1, c2), nil
+}
+
+// RerandomizeWithProof re-encrypts the ciphertext with randomness read from
+// rand and proves that the result re-encrypts c under the same public key.
+// If rand is nil, crypto/rand is used.
+func (c *Ciphertext) RerandomizeWithProof(pubX, pubY *big.Int, rand io.Reader) (*Ciphertext, *DLEQProof, error) {
+	curve := twistededwards.GetEdwardsCurve()
+	r, err := randomScalar(rand, &curve.Order)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate randomness: %w", err)
+	}
+	out, err := c.Rerandomize(pubX, pubY, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, _ := publicKey(pubX, pubY)
+	d1, d2 := rerandomization(pub, r)
+	proof, err := proveDLEQ(&curve.Base, pub, d1, d2, r, rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, proof, nil
+}
+
+// VerifyRerandomization checks that out re-encrypts in under the public key:
+// the proof shows that out - in = (r·G, r·Pub) for some r.
+func VerifyRerandomization(pubX, pubY *big.Int, in, out *Ciphertext, proof *DLEQProof) error {
+	pub, err := publicKey(pubX, pubY)
+	if err != nil {
+		return err
+	}
+	diff, err := out.Sub(in)
+	if err != nil {
+		return err
+	}
+	d1, d2, err := diff.points()
+	if err != nil {
+		return err
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	if err := proof.verifyDLEQ(&curve.Base, pub, d1, d2); err != nil {
+		return fmt.Errorf("invalid rerandomization: %w", err)
+	}
+	return nil
+}
+
+// rerandomization returns the points (r·G, r·Pub) added to a ciphertext.
+func rerandomization(pub *twistededwards.PointAffine, r *big.Int) (d1, d2 *twistededwards.PointAffine) {
+	curve := twistededwards.GetEdwardsCurve()
+	d1, d2 = &twistededwards.PointAffine{}, &twistededwards.PointAffine{}
+	d1.ScalarMultiplication(&curve.Base, r)
+	d2.ScalarMultiplication(pub, r)
+	return d1, d2
+}
+
+// publicKey returns the RTE point of a public key in TE coordinates,
+// validated with ValidatePublicKey.
+func publicKey(x, y *big.Int) (*twistededwards.PointAffine, error) {
+	if err := ValidatePublicKey(x, y); err != nil {
+		return nil, err
+	}
+	return fromTE(x, y)
+}
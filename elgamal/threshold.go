@@ -0,0 +1,378 @@
+package elgamal
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+)
+
+// ThresholdParams describes a threshold ElGamal key shared by Participants
+// parties, indexed from 1, any Threshold of which can decrypt.
+//
+// The key is generated without a trusted dealer, following Pedersen's DKG
+// with Feldman VSS over the BabyJubJub subgroup: every participant runs a
+// Dealer, sends Share(j) privately to each participant j and publishes its
+// Commitments. Each participant checks the shares it receives with
+// VerifyShare and sums them with NewKeyShare. The joint public key, given by
+// JointPublicKey, is the sum of the constant terms of the dealers, whose
+// private key nobody knows.
+type ThresholdParams struct {
+	Threshold    int
+	Participants int
+}
+
+// validate checks that the threshold is between 1 and the number of
+// participants.
+func (p ThresholdParams) validate() error {
+	if p.Participants < 1 || p.Threshold < 1 || p.Threshold > p.Participants {
+		return fmt.Errorf("invalid threshold parameters: threshold %d of %d participants", p.Threshold, p.Participants)
+	}
+	return nil
+}
+
+// validateIndex checks that index identifies a participant.
+func (p ThresholdParams) validateIndex(index int) error {
+	if index < 1 || index > p.Participants {
+		return fmt.Errorf("invalid participant index: got %d, want 1 to %d", index, p.Participants)
+	}
+	return nil
+}
+
+// Dealer is the secret polynomial of degree Threshold-1 that a participant
+// shares during the key generation.
+type Dealer struct {
+	params ThresholdParams
+	index  int
+	coeffs []*big.Int
+}
+
+// NewDealer samples the polynomial of the participant with the given index,
+// reading the coefficients from rand. If rand is nil, crypto/rand is used.
+func NewDealer(params ThresholdParams, index int, rand io.Reader) (*Dealer, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if err := params.validateIndex(index); err != nil {
+		return nil, err
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	d := &Dealer{params: params, index: index, coeffs: make([]*big.Int, params.Threshold)}
+	for i := range d.coeffs {
+		var err error
+		if d.coeffs[i], err = randomScalar(rand, &curve.Order); err != nil {
+			return nil, fmt.Errorf("failed to generate polynomial: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// Index returns the participant index of the dealer.
+func (d *Dealer) Index() int {
+	return d.index
+}
+
+// Commitments returns the Feldman commitments a_k·G to the coefficients of
+// the polynomial, in TE coordinates. They are published to all participants.
+func (d *Dealer) Commitments() [][2]*big.Int {
+	curve := twistededwards.GetEdwardsCurve()
+	commitments := make([][2]*big.Int, len(d.coeffs))
+	for i, a := range d.coeffs {
+		var p twistededwards.PointAffine
+		p.ScalarMultiplication(&curve.Base, a)
+		commitments[i][0], commitments[i][1] = toTE(&p)
+	}
+	return commitments
+}
+
+// Share returns the evaluation of the polynomial at the given participant
+// index, to be sent privately to that participant.
+func (d *Dealer) Share(index int) (*big.Int, error) {
+	if err := d.params.validateIndex(index); err != nil {
+		return nil, err
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	x := big.NewInt(int64(index))
+	share := new(big.Int)
+	for i := len(d.coeffs) - 1; i >= 0; i-- {
+		share.Mul(share, x).Add(share, d.coeffs[i]).Mod(share, &curve.Order)
+	}
+	return share, nil
+}
+
+// VerifyShare checks a share received by the participant with the given
+// index against the commitments of its dealer: share·G = sum C_k·index^k.
+func (p ThresholdParams) VerifyShare(index int, share *big.Int, commitments [][2]*big.Int) error {
+	if err := p.validateIndex(index); err != nil {
+		return err
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	if share == nil || share.Sign() < 0 || share.Cmp(&curve.Order) >= 0 {
+		return fmt.Errorf("invalid share")
+	}
+	expected, err := p.evalCommitments(index, commitments)
+	if err != nil {
+		return err
+	}
+	var got twistededwards.PointAffine
+	got.ScalarMultiplication(&curve.Base, share)
+	if !got.Equal(expected) {
+		return fmt.Errorf("share does not match the dealer commitments")
+	}
+	return nil
+}
+
+// JointPublicKey returns the threshold public key in TE coordinates, ready
+// to be used as encryption_pubkey, from the commitments of the qualified
+// dealers.
+func (p ThresholdParams) JointPublicKey(commitments [][][2]*big.Int) (x, y *big.Int, err error) {
+	if len(commitments) == 0 {
+		return nil, nil, fmt.Errorf("missing dealer commitments")
+	}
+	var pub twistededwards.PointAffine
+	pub.X.SetZero()
+	pub.Y.SetOne()
+	for i, c := range commitments {
+		if err := p.validateCommitments(c); err != nil {
+			return nil, nil, fmt.Errorf("dealer %d: %w", i, err)
+		}
+		c0, _ := fromTE(c[0][0], c[0][1])
+		pub.Add(&pub, c0)
+	}
+	if pub.IsZero() {
+		return nil, nil, fmt.Errorf("invalid public key: identity point")
+	}
+	x, y = toTE(&pub)
+	return x, y, nil
+}
+
+// VerificationKey returns the public counterpart x_j·G of the key share of
+// the participant with the given index, in TE coordinates, from the
+// commitments of the qualified dealers. It is used to verify the partial
+// decryptions of that participant.
+func (p ThresholdParams) VerificationKey(index int, commitments [][][2]*big.Int) ([2]*big.Int, error) {
+	if err := p.validateIndex(index); err != nil {
+		return [2]*big.Int{}, err
+	}
+	if len(commitments) == 0 {
+		return [2]*big.Int{}, fmt.Errorf("missing dealer commitments")
+	}
+	var key twistededwards.PointAffine
+	key.X.SetZero()
+	key.Y.SetOne()
+	for i, c := range commitments {
+		eval, err := p.evalCommitments(index, c)
+		if err != nil {
+			return [2]*big.Int{}, fmt.Errorf("dealer %d: %w", i, err)
+		}
+		key.Add(&key, eval)
+	}
+	x, y := toTE(&key)
+	return [2]*big.Int{x, y}, nil
+}
+
+// KeyShare is the share of the threshold private key held by a participant.
+type KeyShare struct {
+	Index  int
+	Secret *big.Int
+}
+
+// NewKeyShare sums the shares received by the participant with the given
+// index from the qualified dealers, including its own.
+func (p ThresholdParams) NewKeyShare(index int, shares []*big.Int) (*KeyShare, error) {
+	if err := p.validateIndex(index); err != nil {
+		return nil, err
+	}
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("missing shares")
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	secret := new(big.Int)
+	for i, s := range shares {
+		if s == nil || s.Sign() < 0 || s.Cmp(&curve.Order) >= 0 {
+			return nil, fmt.Errorf("invalid share %d", i)
+		}
+		secret.Add(secret, s)
+	}
+	return &KeyShare{Index: index, Secret: secret.Mod(secret, &curve.Order)}, nil
+}
+
+// PartialDecryption is the decryption share D = x_j·C1 of a ciphertext by
+// the participant with the given index, with a proof that it was computed
+// with the key share matching the participant verification key.
+type PartialDecryption struct {
+	Index int         `json:"index"`
+	D     [2]*big.Int `json:"d"`
+	Proof *DLEQProof  `json:"proof"`
+}
+
+// PartialDecrypt returns the decryption share of the ciphertext, reading the
+// proof nonce from rand. If rand is nil, crypto/rand is used.
+func (k *KeyShare) PartialDecrypt(ct *Ciphertext, rand io.Reader) (*PartialDecryption, error) {
+	if k.Secret == nil || k.Secret.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid key share")
+	}
+	c1, _, err := ct.points()
+	if err != nil {
+		return nil, err
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	var key, d twistededwards.PointAffine
+	key.ScalarMultiplication(&curve.Base, k.Secret)
+	d.ScalarMultiplication(c1, k.Secret)
+	proof, err := proveDLEQ(&curve.Base, c1, &key, &d, k.Secret, rand)
+	if err != nil {
+		return nil, err
+	}
+	pd := &PartialDecryption{Index: k.Index, Proof: proof}
+	pd.D[0], pd.D[1] = toTE(&d)
+	return pd, nil
+}
+
+// VerifyPartialDecryption checks that the decryption share of the ciphertext
+// was computed with the key share of the given verification key.
+func VerifyPartialDecryption(ct *Ciphertext, verificationKey [2]*big.Int, pd *PartialDecryption) error {
+	if pd == nil || pd.D[0] == nil || pd.D[1] == nil {
+		return fmt.Errorf("missing partial decryption")
+	}
+	c1, _, err := ct.points()
+	if err != nil {
+		return err
+	}
+	if verificationKey[0] == nil || verificationKey[1] == nil {
+		return fmt.Errorf("missing verification key")
+	}
+	key, err := fromTE(verificationKey[0], verificationKey[1])
+	if err != nil {
+		return fmt.Errorf("invalid verification key: %w", err)
+	}
+	d, err := fromTE(pd.D[0], pd.D[1])
+	if err != nil {
+		return fmt.Errorf("invalid partial decryption: %w", err)
+	}
+	curve := twistededwards.GetEdwardsCurve()
+	if err := pd.Proof.verifyDLEQ(&curve.Base, c1, key, d); err != nil {
+		return fmt.Errorf("invalid partial decryption %d: %w", pd.Index, err)
+	}
+	return nil
+}
+
+// CombinePoint recovers the message point M = C2 - sum l_j·D_j of the
+// ciphertext from at least Threshold decryption shares of distinct
+// participants, where l_j are the Lagrange coefficients at zero. The shares
+// must have been verified with VerifyPartialDecryption.
+func (p ThresholdParams) CombinePoint(ct *Ciphertext, partials []*PartialDecryption) ([2]*big.Int, error) {
+	m, err := p.combine(ct, partials)
+	if err != nil {
+		return [2]*big.Int{}, err
+	}
+	x, y := toTE(m)
+	return [2]*big.Int{x, y}, nil
+}
+
+// Decrypt recovers the message of the ciphertext from at least Threshold
+// decryption shares, as CombinePoint does, and the discrete log table.
+func (p ThresholdParams) Decrypt(ct *Ciphertext, partials []*PartialDecryption, table *DLogTable) (uint64, error) {
+	m, err := p.combine(ct, partials)
+	if err != nil {
+		return 0, err
+	}
+	return table.dlog(m)
+}
+
+func (p ThresholdParams) combine(ct *Ciphertext, partials []*PartialDecryption) (*twistededwards.PointAffine, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if len(partials) < p.Threshold {
+		return nil, fmt.Errorf("not enough partial decryptions: got %d, want %d", len(partials), p.Threshold)
+	}
+	_, c2, err := ct.points()
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, len(partials))
+	seen := make(map[int]bool, len(partials))
+	for i, pd := range partials {
+		if pd == nil || pd.D[0] == nil || pd.D[1] == nil {
+			return nil, fmt.Errorf("missing partial decryption %d", i)
+		}
+		if err := p.validateIndex(pd.Index); err != nil {
+			return nil, err
+		}
+		if seen[pd.Index] {
+			return nil, fmt.Errorf("duplicated partial decryption of participant %d", pd.Index)
+		}
+		seen[pd.Index] = true
+		indexes[i] = pd.Index
+	}
+	var sum twistededwards.PointAffine
+	sum.X.SetZero()
+	sum.Y.SetOne()
+	for i, pd := range partials {
+		d, err := fromTE(pd.D[0], pd.D[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid partial decryption %d: %w", pd.Index, err)
+		}
+		d.ScalarMultiplication(d, lagrangeAtZero(indexes, i))
+		sum.Add(&sum, d)
+	}
+	sum.Neg(&sum)
+	return sum.Add(c2, &sum), nil
+}
+
+// lagrangeAtZero returns the Lagrange coefficient at zero of indexes[i]
+// over indexes, prod m/(m - j) modulo the subgroup order.
+func lagrangeAtZero(indexes []int, i int) *big.Int {
+	curve := twistededwards.GetEdwardsCurve()
+	num, den := big.NewInt(1), big.NewInt(1)
+	j := int64(indexes[i])
+	for k, m := range indexes {
+		if k == i {
+			continue
+		}
+		num.Mul(num, big.NewInt(int64(m))).Mod(num, &curve.Order)
+		den.Mul(den, big.NewInt(int64(m)-j)).Mod(den, &curve.Order)
+	}
+	den.ModInverse(den, &curve.Order)
+	return num.Mul(num, den).Mod(num, &curve.Order)
+}
+
+// validateCommitments checks that a dealer published Threshold commitments
+// in the prime-order subgroup, so that no torsion component reaches the
+// joint key or the verification keys.
+func (p ThresholdParams) validateCommitments(commitments [][2]*big.Int) error {
+	if len(commitments) != p.Threshold {
+		return fmt.Errorf("invalid number of commitments: got %d, want %d", len(commitments), p.Threshold)
+	}
+	for i, c := range commitments {
+		if c[0] == nil || c[1] == nil {
+			return fmt.Errorf("missing commitment %d", i)
+		}
+		if _, err := fromTE(c[0], c[1]); err != nil {
+			return fmt.Errorf("invalid commitment %d: %w", i, err)
+		}
+		if !(&PointTE{X: c[0], Y: c[1]}).InSubgroup() {
+			return fmt.Errorf("invalid commitment %d: point is not in the prime-order subgroup", i)
+		}
+	}
+	return nil
+}
+
+// evalCommitments returns sum C_k·index^k for the commitments of a dealer.
+func (p ThresholdParams) evalCommitments(index int, commitments [][2]*big.Int) (*twistededwards.PointAffine, error) {
+	if err := p.validateCommitments(commitments); err != nil {
+		return nil, err
+	}
+	x := big.NewInt(int64(index))
+	acc := &twistededwards.PointAffine{}
+	acc.X.SetZero()
+	acc.Y.SetOne()
+	for i := len(commitments) - 1; i >= 0; i-- {
+		c, _ := fromTE(commitments[i][0], commitments[i][1])
+		acc.ScalarMultiplication(acc, x)
+		acc.Add(acc, c)
+	}
+	return acc, nil
+}
@@ -0,0 +1,77 @@
+#!/bin/bash
+set -e
+
+# Generates snarkjs proof.json, public.json and verification_key.json fixtures
+# for test/sample_circuit.circom in test/testdata/<fixture>. A fixture is named
+# after its protocol, with a _<curve> suffix for curves other than bn128.
+FIXTURES="${1:-plonk fflonk groth16 groth16_bls12381}"
+
+# Determine circom binary
+if command -v circom &> /dev/null; then
+    CIRCOM_BIN="circom"
+elif [ -f "./deps/circom" ]; then
+    CIRCOM_BIN="./deps/circom"
+else
+    echo "circom not found, run ./prepare-circuit.sh first"
+    exit 1
+fi
+
+# Ensure dependencies are installed
+if [ ! -d "node_modules" ]; then
+    npm install
+fi
+
+SNARKJS="./node_modules/.bin/snarkjs"
+CIRCUIT="test/sample_circuit.circom"
+NAME=$(basename $CIRCUIT .circom)
+BUILD_DIR=$(mktemp -d)
+trap 'rm -rf "$BUILD_DIR"' EXIT
+
+echo '{"address": "11", "vote_id": "22", "inputs_hash": "33"}' > "$BUILD_DIR/input.json"
+
+# prepare_curve compiles the circuit, computes the witness and generates a
+# ptau for the curve, once. FFLONK needs a domain several times larger than
+# the circuit, so the ptau is sized for it rather than for PLONK.
+prepare_curve() {
+  local CURVE_DIR="$BUILD_DIR/$1"
+  if [ -d "$CURVE_DIR" ]; then
+      return
+  fi
+  mkdir "$CURVE_DIR"
+
+  echo "=> Compiling circuit $CIRCUIT for $1"
+  $CIRCOM_BIN $CIRCUIT --r1cs --wasm --prime $1 -o $CURVE_DIR
+  $SNARKJS wtns calculate "$CURVE_DIR/${NAME}_js/$NAME.wasm" "$BUILD_DIR/input.json" "$CURVE_DIR/witness.wtns"
+
+  $SNARKJS powersoftau new $1 10 "$CURVE_DIR/ptau_0.ptau"
+  $SNARKJS powersoftau contribute "$CURVE_DIR/ptau_0.ptau" "$CURVE_DIR/ptau_1.ptau" --name="First contribution" -e="random text"
+  $SNARKJS powersoftau prepare phase2 "$CURVE_DIR/ptau_1.ptau" "$CURVE_DIR/ptau_final.ptau"
+}
+
+for F in $FIXTURES; do
+  case $F in
+    plonk|fflonk|groth16)
+      PROTOCOL=$F
+      CURVE=bn128
+      ;;
+    groth16_bls12381)
+      PROTOCOL=groth16
+      CURVE=bls12381
+      ;;
+    *)
+      echo "unknown fixture $F"
+      exit 1
+      ;;
+  esac
+  prepare_curve $CURVE
+  CURVE_DIR="$BUILD_DIR/$CURVE"
+  OUT_DIR="test/testdata/$F"
+  ZKEY="$BUILD_DIR/${NAME}_$F.zkey"
+  mkdir -p "$OUT_DIR"
+
+  echo "=> Generating $F fixtures in $OUT_DIR"
+  $SNARKJS $PROTOCOL setup "$CURVE_DIR/$NAME.r1cs" "$CURVE_DIR/ptau_final.ptau" $ZKEY
+  $SNARKJS zkey export verificationkey $ZKEY "$OUT_DIR/verification_key.json"
+  $SNARKJS $PROTOCOL prove $ZKEY "$CURVE_DIR/witness.wtns" "$OUT_DIR/proof.json" "$OUT_DIR/public.json"
+  $SNARKJS $PROTOCOL verify "$OUT_DIR/verification_key.json" "$OUT_DIR/public.json" "$OUT_DIR/proof.json"
+done
@@ -0,0 +1,28 @@
+// Writes test/testdata/elgamal/packed_points.json, the packed BabyJubJub
+// points checked by the Go elgamal package and js/test/pack.test.ts, with
+// circomlibjs babyJub.packPoint.
+import * as fs from "fs";
+import * as path from "path";
+import { fileURLToPath } from "url";
+import { buildBabyjub } from "circomlibjs";
+
+const __dirname = path.dirname(fileURLToPath(import.meta.url));
+const out = path.resolve(__dirname, "../../test/testdata/elgamal/packed_points.json");
+
+const babyJub = await buildBabyjub();
+const F = babyJub.F;
+
+// the identity, the base point, multiples of Base8 and the negation of Base8,
+// whose x is greater than (p-1)/2 and sets the sign bit
+const points = [[F.e(0n), F.e(1n)], babyJub.Generator];
+for (let k = 1n; k <= 8n; k++) {
+    points.push(babyJub.mulPointEscalar(babyJub.Base8, k));
+}
+points.push([F.neg(babyJub.Base8[0]), babyJub.Base8[1]]);
+
+const vectors = points.map((p) => ({
+    point: [F.toString(p[0], 10), F.toString(p[1], 10)],
+    packed: Buffer.from(babyJub.packPoint(p)).toString("hex"),
+}));
+fs.writeFileSync(out, JSON.stringify(vectors, null, 2) + "\n");
+console.log(`wrote ${vectors.length} packed points to ${out}`);
@@ -0,0 +1,19 @@
+// Rewrites the inputs of test/testdata/ballot/sequencer_vectors.json with
+// BallotBuilder.generateInputsFromSequencer, for the process data, fields,
+// weight and k of each vector. The Go ballot package and
+// js/test/vectors.test.ts check them. Run `npm run build` first.
+import * as fs from "fs";
+import * as path from "path";
+import { fileURLToPath } from "url";
+import { BallotBuilder } from "../dist/index.js";
+
+const __dirname = path.dirname(fileURLToPath(import.meta.url));
+const file = path.resolve(__dirname, "../../test/testdata/ballot/sequencer_vectors.json");
+
+const builder = await BallotBuilder.build();
+const vectors = JSON.parse(fs.readFileSync(file, "utf-8"));
+for (const v of vectors) {
+    v.inputs = builder.generateInputsFromSequencer(v.process, v.fields, v.weight, v.k);
+}
+fs.writeFileSync(file, JSON.stringify(vectors, null, 2) + "\n");
+console.log(`wrote ${vectors.length} sequencer vectors to ${file}`);
@@ -268,6 +268,10 @@ export class BallotBuilder {
      * @param k - Random k value for encryption
      * @param config - Ballot configuration
      * @param circuitCapacity - Number of fields the circuit supports (default: 8)
+     *
+     * num_fields is taken from `fields.length`, not from `config.numFields`. The
+     * Go ballot package takes it from the ballot mode instead, so both only
+     * agree when the two match, as `generateInputsFromSequencer()` checks.
      */
     generateInputs(
         fields: number[],
@@ -345,7 +349,7 @@ export class BallotBuilder {
      * for the public key automatically.
      * 
      * @param sequencerData - Data from the DAVINCI sequencer
-     * @param fields - The vote field values
+     * @param fields - The vote field values, as many as the ballot mode numFields
      * @param weight - The voter's weight
      * @param k - Optional random k value (generated if not provided)
      * @param circuitCapacity - The number of fields the circuit supports (default: 8)
@@ -375,6 +379,9 @@ export class BallotBuilder {
 
         // Parse ballot mode
         const config = parseBallotMode(sequencerData.ballotMode);
+        if (fields.length !== config.numFields) {
+            throw new Error(`invalid number of fields: got ${fields.length}, ballot mode has ${config.numFields}`);
+        }
 
         // Generate random k if not provided
         const kValue = k ?? this.randomK();
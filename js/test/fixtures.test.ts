@@ -0,0 +1,62 @@
+import { expect } from "chai";
+import * as fs from "fs";
+import * as path from "path";
+import { fileURLToPath } from "url";
+import { BallotBuilder } from "../src/builder.js";
+
+const __filename = fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+
+// Seeded fixture sets written by the Go command `go run ./cmd/ballotvectors`.
+const vectorsDir = path.resolve(__dirname, "../../artifacts/vectors");
+
+describe("Seeded ballot fixture sets", function () {
+    const names = fs.existsSync(vectorsDir)
+        ? fs.readdirSync(vectorsDir)
+            .filter((f) => f.endsWith("_expected.json"))
+            .map((f) => f.replace(/_expected\.json$/, ""))
+        : [];
+    let builder: BallotBuilder;
+
+    before(async () => {
+        builder = await BallotBuilder.build();
+    });
+
+    for (const name of names) {
+        it(`should rebuild the inputs of ${name}`, () => {
+            const input = JSON.parse(fs.readFileSync(path.join(vectorsDir, `${name}_input.json`), "utf-8"));
+            const expected = JSON.parse(fs.readFileSync(path.join(vectorsDir, `${name}_expected.json`), "utf-8"));
+
+            // the encryption key derives from the fixture private key
+            const pubKey = builder.createPubKeyFromTE(input.encryption_pubkey[0], input.encryption_pubkey[1]);
+            const derived = builder.elgamal.babyjub.mulPointEscalar(builder.elgamal.babyjub.Base8, BigInt(expected.private_key));
+            expect(builder.elgamal.F.toString(derived[0], 10)).to.equal(input.encryption_pubkey[0]);
+            expect(builder.elgamal.F.toString(derived[1], 10)).to.equal(input.encryption_pubkey[1]);
+
+            const inputs = builder.generateInputs(
+                input.fields.slice(0, input.num_fields),
+                input.weight,
+                pubKey,
+                input.process_id,
+                input.address,
+                input.k,
+                {
+                    numFields: input.num_fields,
+                    uniqueValues: input.unique_values,
+                    maxValue: input.max_value,
+                    minValue: input.min_value,
+                    maxValueSum: input.max_value_sum,
+                    minValueSum: input.min_value_sum,
+                    costExponent: input.cost_exponent,
+                    costFromWeight: input.cost_from_weight,
+                },
+                expected.n_fields
+            );
+            expect(JSON.parse(JSON.stringify(inputs))).to.deep.equal(input);
+            expect(inputs.cipherfields).to.deep.equal(expected.cipherfields);
+            expect(inputs.vote_id).to.equal(expected.vote_id);
+            expect(inputs.inputs_hash).to.equal(expected.inputs_hash);
+            expect([inputs.address, inputs.vote_id, inputs.inputs_hash]).to.deep.equal(expected.public_signals);
+        });
+    }
+});
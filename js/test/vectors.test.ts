@@ -0,0 +1,46 @@
+import { expect } from "chai";
+import * as fs from "fs";
+import * as path from "path";
+import { fileURLToPath } from "url";
+import { BallotBuilder } from "../src/builder.js";
+import type { SequencerProcessData } from "../src/builder.js";
+
+const __filename = fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+
+// Ballot inputs built from sequencer process data by generateInputsFromSequencer
+// (scripts/sequencer-vectors.mjs, npm run sequencer-vectors) and checked by the
+// Go ballot package (ballot.BuildInputsFromSequencer). Both implementations must
+// produce the same circuit inputs.
+const vectorsPath = path.resolve(__dirname, "../../test/testdata/ballot/sequencer_vectors.json");
+
+interface SequencerVector {
+    name: string;
+    process: SequencerProcessData;
+    fields: number[];
+    weight: number;
+    k: string;
+    inputs: Record<string, unknown>;
+}
+
+describe("Cross-language ballot input vectors", function () {
+    const vectors: SequencerVector[] = JSON.parse(fs.readFileSync(vectorsPath, "utf-8"));
+    let builder: BallotBuilder;
+
+    before(async () => {
+        builder = await BallotBuilder.build();
+    });
+
+    for (const v of vectors) {
+        it(`should match the vector inputs: ${v.name}`, () => {
+            const inputs = builder.generateInputsFromSequencer(v.process, v.fields, v.weight, v.k);
+            expect(JSON.parse(JSON.stringify(inputs))).to.deep.equal(v.inputs);
+        });
+    }
+
+    it("should reject fields that do not match the ballot mode numFields", () => {
+        const v = vectors[0];
+        expect(() => builder.generateInputsFromSequencer(v.process, [1], v.weight, v.k))
+            .to.throw(`invalid number of fields: got 1, ballot mode has ${v.process.ballotMode.numFields}`);
+    });
+});
@@ -0,0 +1,115 @@
+// Package tally provides a gnark circuit proving that published results are
+// the decryption of the accumulated cipherfields of a ballot tally.
+package tally
+
+import (
+	"fmt"
+	"math/big"
+
+	tedwards "github.com/consensys/gnark-crypto/ecc/twistededwards"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/twistededwards"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// ResultBits is the size of the results, which are range checked: a result
+// is only defined modulo the subgroup order as a scalar of G, so without the
+// check result + ℓ would prove the same decryption.
+const ResultBits = 64
+
+// rteScale is -f, the factor mapping the x coordinate of a TE point to RTE:
+// the RTE x coordinate of the TE point with x = 1.
+var rteScale, _ = elgamal.FromTEtoRTE(big.NewInt(1), big.NewInt(1))
+
+// DecryptionCircuit proves knowledge of the private key of EncryptionKey and
+// that Results are the decryption of Cipherfields under it, that is
+// Pub = priv·G and C2 = priv·C1 + result·G for each field, with every result
+// below 2^ResultBits. The encryption key and the cipherfields are public
+// inputs in TE coordinates, as in the ballot inputs, and are converted to RTE
+// inside the circuit.
+type DecryptionCircuit struct {
+	EncryptionKey [2]frontend.Variable      `gnark:",public"`
+	Cipherfields  [][2][2]frontend.Variable `gnark:",public"`
+	Results       []frontend.Variable       `gnark:",public"`
+	PrivateKey    frontend.Variable
+}
+
+// NewDecryptionCircuit returns the circuit definition for tallies of nFields
+// fields, to be compiled.
+func NewDecryptionCircuit(nFields int) *DecryptionCircuit {
+	return &DecryptionCircuit{
+		Cipherfields: make([][2][2]frontend.Variable, nFields),
+		Results:      make([]frontend.Variable, nFields),
+	}
+}
+
+// NewDecryptionAssignment returns the witness assignment proving that results
+// decrypt the cipherfields with the private key of the encryption key, all
+// the points in TE coordinates.
+func NewDecryptionAssignment(priv *big.Int, encryptionKey [2]*big.Int, cipherfields [][2][2]*big.Int, results []uint64,
+) (*DecryptionCircuit, error) {
+	if len(results) != len(cipherfields) {
+		return nil, fmt.Errorf("invalid number of results: got %d, want %d", len(results), len(cipherfields))
+	}
+	if priv == nil || encryptionKey[0] == nil || encryptionKey[1] == nil {
+		return nil, fmt.Errorf("missing private or encryption key")
+	}
+	assignment := NewDecryptionCircuit(len(cipherfields))
+	assignment.EncryptionKey = [2]frontend.Variable{encryptionKey[0], encryptionKey[1]}
+	assignment.PrivateKey = priv
+	for i, cf := range cipherfields {
+		for j := range 2 {
+			if cf[j][0] == nil || cf[j][1] == nil {
+				return nil, fmt.Errorf("missing cipherfield %d", i)
+			}
+			assignment.Cipherfields[i][j] = [2]frontend.Variable{cf[j][0], cf[j][1]}
+		}
+		assignment.Results[i] = results[i]
+	}
+	return assignment, nil
+}
+
+// Define declares the circuit constraints.
+func (c *DecryptionCircuit) Define(api frontend.API) error {
+	if len(c.Results) != len(c.Cipherfields) {
+		return fmt.Errorf("invalid number of results: got %d, want %d", len(c.Results), len(c.Cipherfields))
+	}
+	curve, err := twistededwards.NewEdCurve(api, tedwards.BN254)
+	if err != nil {
+		return err
+	}
+	base := twistededwards.Point{X: curve.Params().Base[0], Y: curve.Params().Base[1]}
+
+	// Pub = priv·G
+	pub := FromTEtoRTE(api, c.EncryptionKey)
+	curve.AssertIsOnCurve(pub)
+	expected := curve.ScalarMul(base, c.PrivateKey)
+	api.AssertIsEqual(pub.X, expected.X)
+	api.AssertIsEqual(pub.Y, expected.Y)
+
+	// C2 = priv·C1 + result·G, with result < 2^ResultBits
+	for i := range c.Cipherfields {
+		api.ToBinary(c.Results[i], ResultBits)
+		c1 := FromTEtoRTE(api, c.Cipherfields[i][0])
+		c2 := FromTEtoRTE(api, c.Cipherfields[i][1])
+		curve.AssertIsOnCurve(c1)
+		curve.AssertIsOnCurve(c2)
+		expected := curve.DoubleBaseScalarMul(c1, base, c.PrivateKey, c.Results[i])
+		api.AssertIsEqual(c2.X, expected.X)
+		api.AssertIsEqual(c2.Y, expected.Y)
+	}
+	return nil
+}
+
+// FromTEtoRTE converts a point in TE coordinates, as used by circom, to the
+// RTE coordinates of the gnark twisted Edwards gadget, x' = x·(-f), y' = y.
+func FromTEtoRTE(api frontend.API, p [2]frontend.Variable) twistededwards.Point {
+	return twistededwards.Point{X: api.Mul(p[0], rteScale), Y: p[1]}
+}
+
+// FromRTEtoTE converts a point in RTE coordinates to the TE coordinates used
+// by circom, x = x'/(-f), y = y'.
+func FromRTEtoTE(api frontend.API, p twistededwards.Point) [2]frontend.Variable {
+	return [2]frontend.Variable{api.Div(p.X, rteScale), p.Y}
+}
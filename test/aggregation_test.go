@@ -1,20 +1,28 @@
 package test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/groth16"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/backend/solidity"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
-	"github.com/consensys/gnark/std/math/emulated"
 	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
 	"github.com/consensys/gnark/test"
 	qt "github.com/frankban/quicktest"
+	"golang.org/x/crypto/sha3"
 
 	"github.com/vocdoni/davinci-circom/circom2gnark"
 	"github.com/vocdoni/davinci-circom/test/testutils"
@@ -27,20 +35,13 @@ const (
 // aggregationCircuit verifies numProofs BN254 Groth16 proofs inside BN254.
 type aggregationCircuit struct {
 	Proofs       [numProofs]stdgroth16.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine]
-	PublicInputs [numProofs][3]emulated.Element[sw_bn254.ScalarField]                                `gnark:",public"`
+	PublicInputs [numProofs]circom2gnark.BallotPublicInputs                                   `gnark:",public"`
 	VerifyingKey stdgroth16.VerifyingKey[sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl] `gnark:"-"`
 }
 
 func (c *aggregationCircuit) Define(api frontend.API) error {
 	for i := range numProofs {
-		verifier, err := stdgroth16.NewVerifier[sw_bn254.ScalarField, sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl](api)
-		if err != nil {
-			return err
-		}
-		witness := stdgroth16.Witness[sw_bn254.ScalarField]{
-			Public: c.PublicInputs[i][:],
-		}
-		err = verifier.AssertProof(c.VerifyingKey, c.Proofs[i], witness, stdgroth16.WithCompleteArithmetic())
+		err := circom2gnark.AssertBallotProofBN254(api, c.VerifyingKey, c.Proofs[i], c.PublicInputs[i], stdgroth16.WithCompleteArithmetic())
 		if err != nil {
 			return fmt.Errorf("proof %d: %w", i, err)
 		}
@@ -53,7 +54,7 @@ func buildBallotInputs() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return json.Marshal(vectors.InputsMap())
+	return json.Marshal(vectors.Map())
 }
 
 func generateCircomProof(wasmPath, zkeyPath string) (proof string, pubSignals []string, err error) {
@@ -92,12 +93,12 @@ func TestCircomAggregation(t *testing.T) {
 	c.Assert(ok, qt.IsTrue, qt.Commentf("native verify failed"))
 	c.Logf("native verify passed: ok=%v", ok)
 
-	placeholder, err := circom2gnark.Circom2GnarkPlaceholderBN254WithVK(vkeyBytes, len(firstPubSignals), true)
+	placeholder, err := circom2gnark.Circom2BallotPlaceholderBN254(vkeyBytes, true)
 	c.Assert(err, qt.IsNil, qt.Commentf("placeholders"))
 
-	var recProofs [numProofs]*circom2gnark.GnarkRecursionProofBN254
+	var recProofs [numProofs]*circom2gnark.BallotRecursionProofBN254
 	pubJSONBytes, _ := json.Marshal(firstPubSignals)
-	recProofs[0], err = circom2gnark.Circom2GnarkProofForRecursionBN254WithVK(vkeyBytes, firstProofJSON, string(pubJSONBytes), true)
+	recProofs[0], err = circom2gnark.Circom2BallotProofForRecursionBN254(vkeyBytes, firstProofJSON, string(pubJSONBytes), true)
 	c.Assert(err, qt.IsNil, qt.Commentf("convert proof 0"))
 
 	for i := 1; i < numProofs; i++ {
@@ -109,7 +110,7 @@ func TestCircomAggregation(t *testing.T) {
 		c.Assert(ok, qt.IsTrue, qt.Commentf("native verify proof %d failed", i))
 
 		pubJSON, _ := json.Marshal(pubSignals)
-		recProofs[i], err = circom2gnark.Circom2GnarkProofForRecursionBN254WithVK(vkeyBytes, proofJSON, string(pubJSON), true)
+		recProofs[i], err = circom2gnark.Circom2BallotProofForRecursionBN254(vkeyBytes, proofJSON, string(pubJSON), true)
 		c.Assert(err, qt.IsNil, qt.Commentf("convert proof %d", i))
 	}
 
@@ -129,10 +130,7 @@ func TestCircomAggregation(t *testing.T) {
 	assignment := &aggregationCircuit{VerifyingKey: placeholder.Vk}
 	for i := 0; i < numProofs; i++ {
 		assignment.Proofs[i] = recProofs[i].Proof
-		if len(recProofs[i].PublicInputs.Public) != 3 {
-			c.Fatal("Expected 3 public inputs, got", len(recProofs[i].PublicInputs.Public))
-		}
-		copy(assignment.PublicInputs[i][:], recProofs[i].PublicInputs.Public)
+		assignment.PublicInputs[i] = recProofs[i].PublicInputs
 	}
 	err = test.IsSolved(placeholderCircuit, assignment, ecc.BN254.ScalarField())
 	c.Assert(err, qt.IsNil, qt.Commentf("assignment not satisfied"))
@@ -154,3 +152,114 @@ func TestCircomAggregation(t *testing.T) {
 
 	c.Logf("BN254 aggregation circuit constraints for %d proofs: %d", numProofs, ccs.GetNbConstraints())
 }
+
+func TestCircomAggregationSolidity(t *testing.T) {
+	c := qt.New(t)
+	if testing.Short() {
+		c.Skip("aggregation setup is slow, skipped in short mode")
+	}
+	// the snarkjs Groth16 fixture, aggregated numProofs times
+	fixture, err := testutils.ReadSnarkJSFixture("groth16")
+	c.Assert(err, qt.IsNil)
+
+	placeholder, err := circom2gnark.Circom2BallotPlaceholderBN254(fixture.Vkey, true)
+	c.Assert(err, qt.IsNil, qt.Commentf("placeholders"))
+	recProof, err := circom2gnark.Circom2BallotProofForRecursionBN254(fixture.Vkey, fixture.Proof, fixture.Public, true)
+	c.Assert(err, qt.IsNil, qt.Commentf("convert proof"))
+	placeholderCircuit := &aggregationCircuit{VerifyingKey: placeholder.Vk}
+	assignment := &aggregationCircuit{VerifyingKey: placeholder.Vk}
+	for i := 0; i < numProofs; i++ {
+		placeholderCircuit.Proofs[i] = placeholder.Proof
+		assignment.Proofs[i] = recProof.Proof
+		assignment.PublicInputs[i] = recProof.PublicInputs
+	}
+
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, placeholderCircuit)
+	c.Assert(err, qt.IsNil, qt.Commentf("compile aggregation circuit"))
+	pk, vk, err := groth16.Setup(ccs)
+	c.Assert(err, qt.IsNil, qt.Commentf("setup aggregation"))
+	wit, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	c.Assert(err, qt.IsNil, qt.Commentf("create witness"))
+	proofAgg, err := groth16.Prove(ccs, pk, wit, solidity.WithProverTargetSolidityVerifier(backend.GROTH16))
+	c.Assert(err, qt.IsNil, qt.Commentf("prove aggregation"))
+	pubWit, err := wit.Public()
+	c.Assert(err, qt.IsNil, qt.Commentf("public witness"))
+
+	var contract bytes.Buffer
+	err = circom2gnark.ExportSolidityGnarkBN254(vk, &contract)
+	c.Assert(err, qt.IsNil)
+	c.Assert(strings.Contains(contract.String(), "function verifyProof("), qt.IsTrue)
+
+	calldata, err := circom2gnark.GnarkProofToSolidityCalldataBN254(proofAgg, pubWit)
+	c.Assert(err, qt.IsNil)
+	packed, err := calldata.Pack()
+	c.Assert(err, qt.IsNil)
+
+	// every emulated public input is exposed as 4 limbs of 64 bits
+	nInputs := numProofs * 3 * 4
+	c.Assert(calldata.PublicInputs, qt.HasLen, nInputs)
+	args, err := abiDecodeStaticArrays(packed, calldata.Signature())
+	c.Assert(err, qt.IsNil)
+	c.Assert(args, qt.HasLen, 4, qt.Commentf("aggregation proofs carry a commitment"))
+	c.Assert(args[3], qt.HasLen, nInputs)
+	pubVector := pubWit.Vector().(bn254fr.Vector)
+	for i, input := range args[3] {
+		c.Assert(input.String(), qt.Equals, pubVector[i].String())
+	}
+
+	// rebuild the proof from the decoded words and verify it natively
+	decoded := &groth16_bn254.Proof{}
+	setG1 := func(p *bn254.G1Affine, w []*big.Int) {
+		p.X.SetBigInt(w[0])
+		p.Y.SetBigInt(w[1])
+	}
+	setG1(&decoded.Ar, args[0][0:2])
+	decoded.Bs.X.A1.SetBigInt(args[0][2])
+	decoded.Bs.X.A0.SetBigInt(args[0][3])
+	decoded.Bs.Y.A1.SetBigInt(args[0][4])
+	decoded.Bs.Y.A0.SetBigInt(args[0][5])
+	setG1(&decoded.Krs, args[0][6:8])
+	decoded.Commitments = make([]bn254.G1Affine, len(args[1])/2)
+	for i := range decoded.Commitments {
+		setG1(&decoded.Commitments[i], args[1][2*i:2*i+2])
+	}
+	setG1(&decoded.CommitmentPok, args[2])
+	err = groth16.Verify(decoded, vk, pubWit, solidity.WithVerifierTargetSolidityVerifier(backend.GROTH16))
+	c.Assert(err, qt.IsNil, qt.Commentf("verify decoded aggregation proof"))
+}
+
+// abiDecodeStaticArrays decodes calldata for a function whose arguments are
+// all static uint256 arrays, such as verifyProof(uint256[8],uint256[3]). It
+// checks the selector and the length, and returns one slice per argument.
+func abiDecodeStaticArrays(calldata []byte, signature string) ([][]*big.Int, error) {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(signature))
+	if len(calldata) < 4 || !bytes.Equal(calldata[:4], h.Sum(nil)[:4]) {
+		return nil, fmt.Errorf("selector mismatch for %s", signature)
+	}
+	lparen, rparen := strings.Index(signature, "("), strings.LastIndex(signature, ")")
+	if lparen < 0 || rparen < lparen {
+		return nil, fmt.Errorf("malformed signature %s", signature)
+	}
+	var args [][]*big.Int
+	offset := 4
+	for _, arg := range strings.Split(signature[lparen+1:rparen], ",") {
+		var size int
+		if _, err := fmt.Sscanf(arg, "uint256[%d]", &size); err != nil {
+			return nil, fmt.Errorf("unsupported argument type %s", arg)
+		}
+		words := make([]*big.Int, size)
+		for i := range words {
+			if offset+32 > len(calldata) {
+				return nil, fmt.Errorf("calldata too short")
+			}
+			words[i] = new(big.Int).SetBytes(calldata[offset : offset+32])
+			offset += 32
+		}
+		args = append(args, words)
+	}
+	if offset != len(calldata) {
+		return nil, fmt.Errorf("unexpected trailing calldata: %d bytes", len(calldata)-offset)
+	}
+	return args, nil
+}
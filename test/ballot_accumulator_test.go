@@ -0,0 +1,139 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/ballot/testvectors"
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+func TestBallotAccumulator(t *testing.T) {
+	c := qt.New(t)
+	table, err := elgamal.NewDLogTable(20)
+	c.Assert(err, qt.IsNil)
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	build := func(fields ...uint64) *ballot.Inputs {
+		inputs, err := ballot.BuildInputs(&ballot.Ballot{
+			Mode:          ballot.Mode{NumFields: len(fields), MaxValue: 16, MaxValueSum: 1125, CostExponent: 2},
+			ProcessID:     big.NewInt(1),
+			Address:       big.NewInt(2),
+			Weight:        1,
+			EncryptionKey: [2]*big.Int{pubX, pubY},
+			Fields:        fields,
+		})
+		c.Assert(err, qt.IsNil)
+		return inputs
+	}
+
+	acc := ballot.NewAccumulator()
+	c.Assert(acc.Fields(), qt.HasLen, ballot.NFields)
+	tally, err := acc.Decrypt(priv, table)
+	c.Assert(err, qt.IsNil)
+	c.Assert(tally, qt.DeepEquals, make([]uint64, ballot.NFields))
+
+	first := build(1, 2, 3)
+	c.Assert(acc.Add(first.Cipherfields), qt.IsNil)
+	c.Assert(acc.Add(build(4, 0, 6).Cipherfields), qt.IsNil)
+	c.Assert(acc.AddWeighted(build(1, 1, 1).Cipherfields, 10), qt.IsNil)
+	c.Assert(acc.Ballots(), qt.Equals, 3)
+	tally, err = acc.Decrypt(priv, table)
+	c.Assert(err, qt.IsNil)
+	c.Assert(tally, qt.DeepEquals, []uint64{15, 12, 19, 0, 0, 0, 0, 0})
+
+	// overwrite the first ballot
+	c.Assert(acc.Sub(first.Cipherfields), qt.IsNil)
+	c.Assert(acc.Add(build(0, 0, 16).Cipherfields), qt.IsNil)
+	c.Assert(acc.Ballots(), qt.Equals, 3)
+	tally, err = acc.Decrypt(priv, table)
+	c.Assert(err, qt.IsNil)
+	c.Assert(tally, qt.DeepEquals, []uint64{14, 10, 32, 0, 0, 0, 0, 0})
+	c.Assert(acc.Cipherfields(), qt.HasLen, ballot.NFields)
+
+	// invalid cipherfields leave the tally untouched
+	bad := build(1).Cipherfields
+	bad[3][1][0] = big.NewInt(5)
+	c.Assert(acc.Add(bad), qt.ErrorMatches, "invalid cipherfield 3: invalid C2: .*")
+	c.Assert(acc.Add(bad[:2]), qt.ErrorMatches, "invalid number of cipherfields: got 2, want 8")
+	c.Assert(acc.Ballots(), qt.Equals, 3)
+	tally, err = acc.Decrypt(priv, table)
+	c.Assert(err, qt.IsNil)
+	c.Assert(tally, qt.DeepEquals, []uint64{14, 10, 32, 0, 0, 0, 0, 0})
+
+	c.Assert(ballot.NewAccumulator().Sub(first.Cipherfields), qt.ErrorMatches, "no ballots to remove")
+
+	// accumulators follow the circuit size
+	circuit, err := ballot.NewCircuit(3)
+	c.Assert(err, qt.IsNil)
+	c.Assert(circuit.NewAccumulator().Add(first.Cipherfields), qt.ErrorMatches, "invalid number of cipherfields: got 8, want 3")
+}
+
+func TestRerandomizeCipherfields(t *testing.T) {
+	c := qt.New(t)
+	set, err := testvectors.Lookup("rating")
+	c.Assert(err, qt.IsNil)
+	vector, err := testvectors.Generate(set)
+	c.Assert(err, qt.IsNil)
+	in, key := vector.Inputs.Cipherfields, vector.Inputs.EncryptionKey
+
+	out, proofs, err := ballot.RerandomizeCipherfields(in, key, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(out, qt.HasLen, ballot.NFields)
+	c.Assert(proofs, qt.HasLen, ballot.NFields)
+	c.Assert(ballot.VerifyRerandomizedCipherfields(key, in, out, proofs), qt.IsNil)
+
+	// the votes are unchanged but the ciphertexts are not
+	table, err := elgamal.NewDLogTable(16)
+	c.Assert(err, qt.IsNil)
+	for i := range out {
+		c.Assert(out[i][0][0].Cmp(in[i][0][0]), qt.Not(qt.Equals), 0)
+		m, err := elgamal.Decrypt(vector.PrivateKey, out[i][0], out[i][1], table)
+		c.Assert(err, qt.IsNil)
+		c.Assert(m, qt.Equals, vector.Inputs.Fields[i])
+	}
+
+	// swapping two fields breaks the proofs
+	swapped := append([][2][2]*big.Int{}, out...)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	c.Assert(ballot.VerifyRerandomizedCipherfields(key, in, swapped, proofs), qt.ErrorMatches, "cipherfield 0: invalid rerandomization: invalid proof")
+	c.Assert(ballot.VerifyRerandomizedCipherfields(key, in, out, proofs[1:]), qt.ErrorMatches, "invalid number of rerandomized cipherfields.*")
+}
+
+func TestBallotResultsProof(t *testing.T) {
+	c := qt.New(t)
+	table, err := elgamal.NewDLogTable(16)
+	c.Assert(err, qt.IsNil)
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	key := [2]*big.Int{pubX, pubY}
+
+	acc := ballot.NewAccumulator()
+	for _, fields := range [][]uint64{{2, 0, 1}, {1, 1, 1}, {0, 3, 0}} {
+		inputs, err := ballot.BuildInputs(&ballot.Ballot{
+			Mode:          ballot.Mode{NumFields: len(fields), MaxValue: 3},
+			ProcessID:     big.NewInt(1),
+			Address:       big.NewInt(2),
+			Weight:        1,
+			EncryptionKey: key,
+			Fields:        fields,
+		})
+		c.Assert(err, qt.IsNil)
+		c.Assert(acc.Add(inputs.Cipherfields), qt.IsNil)
+	}
+	results, proofs, err := acc.DecryptWithProofs(priv, table, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.DeepEquals, []uint64{3, 4, 2, 0, 0, 0, 0, 0})
+
+	// anyone can check the results from the accumulated cipherfields
+	cipherfields := acc.Cipherfields()
+	c.Assert(ballot.VerifyResults(key, cipherfields, results, proofs), qt.IsNil)
+
+	forged := append([]uint64{}, results...)
+	forged[1]++
+	c.Assert(ballot.VerifyResults(key, cipherfields, forged, proofs), qt.ErrorMatches, "field 1: invalid decryption: invalid proof")
+	c.Assert(ballot.VerifyResults(key, cipherfields, results[:3], proofs), qt.ErrorMatches, "invalid number of results: got 3 with 8 proofs, want 8")
+}
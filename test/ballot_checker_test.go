@@ -2,11 +2,13 @@ package test
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"strconv"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/davinci-circom/ballot"
 	"github.com/vocdoni/davinci-circom/test/testutils"
 )
 
@@ -28,192 +30,203 @@ func ballotToStrings(vals []int64) []string {
 	return out
 }
 
-func TestBallotChecker(t *testing.T) {
-	c := qt.New(t)
-	type tc struct {
-		name         string
-		fields       []int64 // raw field values (<= 8 non‑zero entries)
-		maxCount     int     // logical field count provided by the ballot
-		forceUnique  bool    // uniqueness flag
-		maxValue     int
-		minValue     int
-		maxTotalCost int
-		minTotalCost int
-		costExp      int
-		expectPass   bool
-	}
+// ballotCheckerCase is a ballot checked against the BallotChecker rules. For
+// invalid ballots, rule is the violated rule.
+type ballotCheckerCase struct {
+	name         string
+	fields       []int64 // raw field values (<= 8 non‑zero entries)
+	maxCount     int     // logical field count provided by the ballot
+	forceUnique  bool    // uniqueness flag
+	maxValue     int
+	minValue     int
+	maxTotalCost int
+	minTotalCost int
+	costExp      int
+	expectPass   bool
+	rule         ballot.Rule
+}
 
-	cases := []tc{
-		{
-			name:         "Simple 5‑star rating – valid",
-			fields:       []int64{3, 2, 5},
-			maxCount:     3,
-			forceUnique:  true,
-			maxValue:     5,
-			minValue:     0,
-			maxTotalCost: 15,
-			minTotalCost: 0,
-			costExp:      1,
-			expectPass:   true,
-		},
-		{
-			name:         "Duplicate values with uniqueness required – invalid",
-			fields:       []int64{3, 3, 1},
-			maxCount:     3,
-			forceUnique:  true,
-			maxValue:     5,
-			minValue:     0,
-			maxTotalCost: 16,
-			minTotalCost: 0,
-			costExp:      1,
-			expectPass:   false,
-		},
-		{
-			name:         "Maxvalue is correctly verified and maxTotalCost=0 is ignored – valid",
-			fields:       []int64{50, 49, 48},
-			maxCount:     3,
-			forceUnique:  false,
-			maxValue:     50,
-			minValue:     0,
-			maxTotalCost: 0,
-			minTotalCost: 0,
-			costExp:      1,
-			expectPass:   true,
-		},
-		{
-			name:         "Value exceeds maxValue – invalid",
-			fields:       []int64{13, 0, 0},
-			maxCount:     3,
-			forceUnique:  false,
-			maxValue:     12,
-			minValue:     0,
-			maxTotalCost: 15,
-			minTotalCost: 0,
-			costExp:      1,
-			expectPass:   false,
-		},
-		{
-			name:         "Value underflows minValue – invalid",
-			fields:       []int64{1, 0, 0},
-			maxCount:     3,
-			forceUnique:  false,
-			maxValue:     11,
-			minValue:     5,
-			maxTotalCost: 1000,
-			minTotalCost: 0,
-			costExp:      1,
-			expectPass:   false,
-		},
-		{
-			name:         "Quadratic voting cost within limit – valid",
-			fields:       []int64{2, 2, 2}, // cost = 4+4+4 = 12
-			maxCount:     3,
-			forceUnique:  false,
-			maxValue:     4,
-			minValue:     0,
-			maxTotalCost: 12,
-			minTotalCost: 0,
-			costExp:      2,
-			expectPass:   true,
-		},
-		{
-			name:         "Quadratic voting cost exceeds limit – invalid",
-			fields:       []int64{3, 2, 1}, // cost = 9+4+1 = 14 > 13
-			maxCount:     3,
-			forceUnique:  false,
-			maxValue:     4,
-			minValue:     0,
-			maxTotalCost: 13,
-			minTotalCost: 0,
-			costExp:      2,
-			expectPass:   false,
-		},
-		{
-			name:         "MinTotalCost not reached – invalid",
-			fields:       []int64{2, 0, 0}, // cost = 4 < 5
-			maxCount:     3,
-			forceUnique:  false,
-			maxValue:     4,
-			minValue:     0,
-			maxTotalCost: 20,
-			minTotalCost: 5,
-			costExp:      2,
-			expectPass:   false,
-		},
-		{
-			name:         "Duplicates allowed when uniqueness off – valid",
-			fields:       []int64{5, 5, 0},
-			maxCount:     3,
-			forceUnique:  false,
-			maxValue:     5,
-			minValue:     0,
-			maxTotalCost: 15,
-			minTotalCost: 0,
-			costExp:      1,
-			expectPass:   true,
-		},
-		{
-			name:         "Approval voting – exactly 3 of 6 chosen – valid",
-			fields:       []int64{1, 0, 1, 0, 1, 0},
-			maxCount:     6,
-			forceUnique:  false,
-			maxValue:     1,
-			minValue:     0,
-			maxTotalCost: 3,
-			minTotalCost: 3,
-			costExp:      1,
-			expectPass:   true,
-		},
-		{
-			name:         "Approval voting – choose 4 out of 6 (exceeds limit) – invalid",
-			fields:       []int64{1, 1, 1, 1, 0, 0}, // cost 4 > 3
-			maxCount:     6,
-			forceUnique:  false,
-			maxValue:     1,
-			minValue:     0,
-			maxTotalCost: 3,
-			minTotalCost: 3,
-			costExp:      1,
-			expectPass:   false,
-		},
-		{
-			name:         "Ranked‑choice voting – unique ranks 1..3 – valid",
-			fields:       []int64{1, 2, 3}, // sum = 6
-			maxCount:     3,
-			forceUnique:  true,
-			maxValue:     3,
-			minValue:     1,
-			maxTotalCost: 6,
-			minTotalCost: 6,
-			costExp:      1,
-			expectPass:   true,
-		},
-		{
-			name:         "Ranked‑choice voting – duplicate rank – invalid",
-			fields:       []int64{1, 1, 2},
-			maxCount:     3,
-			forceUnique:  true,
-			maxValue:     3,
-			minValue:     1,
-			maxTotalCost: 6,
-			minTotalCost: 6,
-			costExp:      1,
-			expectPass:   false,
-		},
-		{
-			name:         "All zeros but minTotalCost positive – invalid",
-			fields:       []int64{0, 0, 0},
-			maxCount:     3,
-			forceUnique:  false,
-			maxValue:     5,
-			minValue:     0,
-			maxTotalCost: 10,
-			minTotalCost: 1,
-			costExp:      1,
-			expectPass:   false,
-		},
-	}
+var ballotCheckerCases = []ballotCheckerCase{
+	{
+		name:         "Simple 5‑star rating – valid",
+		fields:       []int64{3, 2, 5},
+		maxCount:     3,
+		forceUnique:  true,
+		maxValue:     5,
+		minValue:     0,
+		maxTotalCost: 15,
+		minTotalCost: 0,
+		costExp:      1,
+		expectPass:   true,
+	},
+	{
+		name:         "Duplicate values with uniqueness required – invalid",
+		fields:       []int64{3, 3, 1},
+		maxCount:     3,
+		forceUnique:  true,
+		maxValue:     5,
+		minValue:     0,
+		maxTotalCost: 16,
+		minTotalCost: 0,
+		costExp:      1,
+		expectPass:   false,
+		rule:         ballot.RuleUniqueValues,
+	},
+	{
+		name:         "Maxvalue is correctly verified and maxTotalCost=0 is ignored – valid",
+		fields:       []int64{50, 49, 48},
+		maxCount:     3,
+		forceUnique:  false,
+		maxValue:     50,
+		minValue:     0,
+		maxTotalCost: 0,
+		minTotalCost: 0,
+		costExp:      1,
+		expectPass:   true,
+	},
+	{
+		name:         "Value exceeds maxValue – invalid",
+		fields:       []int64{13, 0, 0},
+		maxCount:     3,
+		forceUnique:  false,
+		maxValue:     12,
+		minValue:     0,
+		maxTotalCost: 15,
+		minTotalCost: 0,
+		costExp:      1,
+		expectPass:   false,
+		rule:         ballot.RuleMaxValue,
+	},
+	{
+		name:         "Value underflows minValue – invalid",
+		fields:       []int64{1, 0, 0},
+		maxCount:     3,
+		forceUnique:  false,
+		maxValue:     11,
+		minValue:     5,
+		maxTotalCost: 1000,
+		minTotalCost: 0,
+		costExp:      1,
+		expectPass:   false,
+		rule:         ballot.RuleMinValue,
+	},
+	{
+		name:         "Quadratic voting cost within limit – valid",
+		fields:       []int64{2, 2, 2}, // cost = 4+4+4 = 12
+		maxCount:     3,
+		forceUnique:  false,
+		maxValue:     4,
+		minValue:     0,
+		maxTotalCost: 12,
+		minTotalCost: 0,
+		costExp:      2,
+		expectPass:   true,
+	},
+	{
+		name:         "Quadratic voting cost exceeds limit – invalid",
+		fields:       []int64{3, 2, 1}, // cost = 9+4+1 = 14 > 13
+		maxCount:     3,
+		forceUnique:  false,
+		maxValue:     4,
+		minValue:     0,
+		maxTotalCost: 13,
+		minTotalCost: 0,
+		costExp:      2,
+		expectPass:   false,
+		rule:         ballot.RuleMaxValueSum,
+	},
+	{
+		name:         "MinTotalCost not reached – invalid",
+		fields:       []int64{2, 0, 0}, // cost = 4 < 5
+		maxCount:     3,
+		forceUnique:  false,
+		maxValue:     4,
+		minValue:     0,
+		maxTotalCost: 20,
+		minTotalCost: 5,
+		costExp:      2,
+		expectPass:   false,
+		rule:         ballot.RuleMinValueSum,
+	},
+	{
+		name:         "Duplicates allowed when uniqueness off – valid",
+		fields:       []int64{5, 5, 0},
+		maxCount:     3,
+		forceUnique:  false,
+		maxValue:     5,
+		minValue:     0,
+		maxTotalCost: 15,
+		minTotalCost: 0,
+		costExp:      1,
+		expectPass:   true,
+	},
+	{
+		name:         "Approval voting – exactly 3 of 6 chosen – valid",
+		fields:       []int64{1, 0, 1, 0, 1, 0},
+		maxCount:     6,
+		forceUnique:  false,
+		maxValue:     1,
+		minValue:     0,
+		maxTotalCost: 3,
+		minTotalCost: 3,
+		costExp:      1,
+		expectPass:   true,
+	},
+	{
+		name:         "Approval voting – choose 4 out of 6 (exceeds limit) – invalid",
+		fields:       []int64{1, 1, 1, 1, 0, 0}, // cost 4 > 3
+		maxCount:     6,
+		forceUnique:  false,
+		maxValue:     1,
+		minValue:     0,
+		maxTotalCost: 3,
+		minTotalCost: 3,
+		costExp:      1,
+		expectPass:   false,
+		rule:         ballot.RuleMaxValueSum,
+	},
+	{
+		name:         "Ranked‑choice voting – unique ranks 1..3 – valid",
+		fields:       []int64{1, 2, 3}, // sum = 6
+		maxCount:     3,
+		forceUnique:  true,
+		maxValue:     3,
+		minValue:     1,
+		maxTotalCost: 6,
+		minTotalCost: 6,
+		costExp:      1,
+		expectPass:   true,
+	},
+	{
+		name:         "Ranked‑choice voting – duplicate rank – invalid",
+		fields:       []int64{1, 1, 2},
+		maxCount:     3,
+		forceUnique:  true,
+		maxValue:     3,
+		minValue:     1,
+		maxTotalCost: 6,
+		minTotalCost: 6,
+		costExp:      1,
+		expectPass:   false,
+		rule:         ballot.RuleUniqueValues,
+	},
+	{
+		name:         "All zeros but minTotalCost positive – invalid",
+		fields:       []int64{0, 0, 0},
+		maxCount:     3,
+		forceUnique:  false,
+		maxValue:     5,
+		minValue:     0,
+		maxTotalCost: 10,
+		minTotalCost: 1,
+		costExp:      1,
+		expectPass:   false,
+		rule:         ballot.RuleMinValueSum,
+	},
+}
 
+func TestBallotChecker(t *testing.T) {
+	c := qt.New(t)
 	// Get artifact paths
 	wasmPath, err := testutils.GetArtifactPath(testutils.BallotCheckerWasm)
 	c.Assert(err, qt.IsNil)
@@ -222,7 +235,7 @@ func TestBallotChecker(t *testing.T) {
 	vkeyPath, err := testutils.GetArtifactPath(testutils.BallotCheckerVkey)
 	c.Assert(err, qt.IsNil)
 
-	for _, tc := range cases {
+	for _, tc := range ballotCheckerCases {
 		t.Run(tc.name, func(t *testing.T) {
 			c := qt.New(t)
 
@@ -276,3 +289,83 @@ func TestBallotChecker(t *testing.T) {
 		})
 	}
 }
+
+func TestBallotCheckerNative(t *testing.T) {
+	for _, tc := range ballotCheckerCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := qt.New(t)
+			fields := make([]uint64, len(tc.fields))
+			for i, v := range tc.fields {
+				fields[i] = uint64(v)
+			}
+			mode := ballot.Mode{
+				NumFields:    tc.maxCount,
+				UniqueValues: tc.forceUnique,
+				MaxValue:     uint64(tc.maxValue),
+				MinValue:     uint64(tc.minValue),
+				MaxValueSum:  uint64(tc.maxTotalCost),
+				MinValueSum:  uint64(tc.minTotalCost),
+				CostExponent: tc.costExp,
+			}
+			err := ballot.CheckFields(mode, fields, 0)
+			if tc.expectPass {
+				c.Assert(err, qt.IsNil)
+				return
+			}
+			var errs ballot.CheckErrors
+			c.Assert(errors.As(err, &errs), qt.IsTrue)
+			c.Assert(errs[0].Rule, qt.Equals, tc.rule)
+		})
+	}
+}
+
+func TestBallotCheckerErrors(t *testing.T) {
+	c := qt.New(t)
+	mode := ballot.Mode{
+		NumFields:    4,
+		UniqueValues: true,
+		MaxValue:     10,
+		MinValue:     1,
+		MaxValueSum:  20,
+		MinValueSum:  5,
+		CostExponent: 1,
+	}
+	// inactive fields are not checked
+	c.Assert(ballot.CheckFields(mode, []uint64{1, 2, 3, 4, 4, 99}, 0), qt.IsNil)
+
+	err := ballot.CheckFields(mode, []uint64{3, 11, 3, 0}, 0)
+	c.Assert(err, qt.ErrorMatches, "field 2 violates unique_values: value 3 repeats field 0; "+
+		"field 1 violates max_value: value 11 is greater than 10; "+
+		"field 3 violates min_value: value 0 is less than 1")
+	var errs ballot.CheckErrors
+	c.Assert(errors.As(err, &errs), qt.IsTrue)
+	c.Assert(errs, qt.HasLen, 3)
+	c.Assert(errs[0].Field, qt.Equals, 2)
+	c.Assert(errs[0].Rule, qt.Equals, ballot.RuleUniqueValues)
+
+	// the weight bounds the cost when cost_from_weight is set
+	mode.CostFromWeight = true
+	c.Assert(ballot.CheckFields(mode, []uint64{1, 2, 3, 4}, 10), qt.IsNil)
+	err = ballot.CheckFields(mode, []uint64{1, 2, 3, 4}, 9)
+	c.Assert(err, qt.ErrorMatches, "ballot violates weight: total cost 10 is greater than 9")
+
+	// max_value_sum = 0 leaves the cost unbounded, but not beyond 128 bits
+	mode = ballot.Mode{NumFields: 2, MaxValue: 1 << 62, CostExponent: 3}
+	c.Assert(ballot.CheckFields(mode, []uint64{1 << 40, 1 << 40}, 0), qt.IsNil)
+	err = ballot.CheckFields(mode, []uint64{1 << 62, 1}, 0)
+	c.Assert(err, qt.ErrorMatches, "ballot violates max_value_sum: total cost .* overflows 128 bits; ballot violates min_value_sum: .*")
+
+	// missing fields are zeros, which cost 0^0 = 1 with a cost exponent of 0
+	mode = ballot.Mode{NumFields: 4, MaxValue: 5, MaxValueSum: 4, MinValueSum: 4, CostExponent: 0}
+	c.Assert(ballot.CheckFields(mode, []uint64{3, 5}, 0), qt.IsNil)
+	c.Assert(ballot.CheckFields(mode, []uint64{3, 5, 0, 0}, 0), qt.IsNil)
+	mode.MaxValueSum, mode.MinValueSum = 2, 2
+	err = ballot.CheckFields(mode, []uint64{3, 5}, 0)
+	c.Assert(err, qt.ErrorMatches, "ballot violates max_value_sum: total cost 4 is greater than 2")
+
+	// num_fields is bounded by the circuit fields
+	mode = ballot.Mode{NumFields: ballot.NFields + 1, MaxValue: 1 << 62, CostExponent: 3}
+	err = ballot.CheckFields(mode, nil, 0)
+	c.Assert(err, qt.ErrorMatches, "ballot violates num_fields: num_fields 9 is greater than 8")
+	c.Assert(ballot.CheckFields(mode, make([]uint64, ballot.NFields+1), 0), qt.ErrorMatches, "ballot violates num_fields: got 9 fields, max 8")
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/elgamal"
 	"github.com/vocdoni/davinci-circom/test/testutils"
 )
 
@@ -21,17 +23,19 @@ func TestBallotCipher(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 
 	// encrypt ballot
-	_, pubX, pubY := testutils.GenerateKeyPair()
-	
-	k, err := testutils.RandomK()
+	_, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil, qt.Commentf("Error generating key pair"))
+
+	k, err := ballot.RandomK(nil)
 	c.Assert(err, qt.IsNil, qt.Commentf("Error generating random k"))
 
 	// Circuit derives k_i = Poseidon(k_{i-1}) per field; we only have one field.
-	ks, err := testutils.DerivePoseidonChain(k, 1)
+	ks, err := ballot.DerivePoseidonChain(k, 1)
 	c.Assert(err, qt.IsNil, qt.Commentf("derive ks"))
 
 	msg := big.NewInt(3)
-	c1, c2 := testutils.Encrypt(msg, pubX, pubY, ks[1])
+	c1, c2, err := elgamal.Encrypt(msg, pubX, pubY, ks[1])
+	c.Assert(err, qt.IsNil, qt.Commentf("Error encrypting"))
 	inputs := map[string]any{
 		"encryption_pubkey": []string{pubX.String(), pubY.String()},
 		"k":                 k.String(),
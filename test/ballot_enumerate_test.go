@@ -0,0 +1,157 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/ballot/testvectors"
+)
+
+// bruteForceBallots lists the ballots accepted by CheckFields with values up
+// to max_value + 1, in lexicographic order.
+func bruteForceBallots(mode ballot.Mode, weight uint64) [][]uint64 {
+	var ballots [][]uint64
+	fields := make([]uint64, mode.NumFields)
+	var walk func(i int)
+	walk = func(i int) {
+		if i == len(fields) {
+			if ballot.CheckFields(mode, fields, weight) == nil {
+				ballots = append(ballots, slices.Clone(fields))
+			}
+			return
+		}
+		for v := range mode.MaxValue + 2 {
+			fields[i] = v
+			walk(i + 1)
+		}
+	}
+	walk(0)
+	return ballots
+}
+
+func TestBallotEnumerate(t *testing.T) {
+	c := qt.New(t)
+	for _, tc := range []struct {
+		name   string
+		mode   ballot.Mode
+		weight uint64
+	}{
+		{"plain", ballot.Mode{NumFields: 3, MaxValue: 3, CostExponent: 1}, 0},
+		{"bounded", ballot.Mode{NumFields: 3, MaxValue: 4, MinValue: 1, MaxValueSum: 7, MinValueSum: 5, CostExponent: 1}, 0},
+		{"quadratic", ballot.Mode{NumFields: 4, MaxValue: 3, MaxValueSum: 9, CostExponent: 2}, 0},
+		{"min sum only", ballot.Mode{NumFields: 3, MaxValue: 5, MinValueSum: 20, CostExponent: 2}, 0},
+		{"exponent 0", ballot.Mode{NumFields: 3, MaxValue: 2, MaxValueSum: 3, MinValueSum: 3}, 0},
+		{"unique", ballot.Mode{NumFields: 3, UniqueValues: true, MaxValue: 5, MaxValueSum: 9, CostExponent: 1}, 0},
+		{"unique min sum", ballot.Mode{NumFields: 3, UniqueValues: true, MaxValue: 6, MinValueSum: 4, CostExponent: 2}, 0},
+		{"unique exponent 0", ballot.Mode{NumFields: 2, UniqueValues: true, MaxValue: 4, MinValue: 1}, 0},
+		{"weight", ballot.Mode{NumFields: 3, MaxValue: 4, MaxValueSum: 1, CostExponent: 2, CostFromWeight: true}, 12},
+		{"empty", ballot.Mode{NumFields: 0, CostExponent: 1}, 0},
+		{"impossible", ballot.Mode{NumFields: 2, MaxValue: 3, MaxValueSum: 1, MinValueSum: 1, CostExponent: 2, CostFromWeight: true}, 0},
+	} {
+		c.Run(tc.name, func(c *qt.C) {
+			want := bruteForceBallots(tc.mode, tc.weight)
+			count, err := ballot.CountBallots(tc.mode, tc.weight)
+			c.Assert(err, qt.IsNil)
+			c.Assert(count.Int64(), qt.Equals, int64(len(want)))
+			got, err := ballot.EnumerateBallots(tc.mode, tc.weight, len(want))
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.DeepEquals, want)
+			if len(want) > 0 {
+				_, err = ballot.EnumerateBallots(tc.mode, tc.weight, len(want)-1)
+				c.Assert(err, qt.ErrorMatches, "too many ballots to enumerate: .*")
+			}
+		})
+	}
+
+	// counts of the presets
+	for _, tc := range []struct {
+		mode  func() (ballot.Mode, error)
+		count string
+	}{
+		{func() (ballot.Mode, error) { return ballot.SingleChoice(8) }, "8"},
+		{func() (ballot.Mode, error) { return ballot.Approval(6, 1, 3) }, "41"},
+		{func() (ballot.Mode, error) { return ballot.RankedChoice(8) }, "40320"},
+		{func() (ballot.Mode, error) { return ballot.Rating(8, 1, 10) }, "100000000"},
+		{func() (ballot.Mode, error) { return ballot.Quadratic(2, 4) }, "6"},
+	} {
+		mode, err := tc.mode()
+		c.Assert(err, qt.IsNil)
+		count, err := ballot.CountBallots(mode, 0)
+		c.Assert(err, qt.IsNil)
+		c.Assert(count.String(), qt.Equals, tc.count, qt.Commentf(ballot.Describe(mode)))
+	}
+
+	// the fixture sets count their circuit fields
+	set, err := testvectors.Lookup("rating")
+	c.Assert(err, qt.IsNil)
+	count, err := ballot.CountBallots(set.Mode, set.Weight)
+	c.Assert(err, qt.IsNil)
+	c.Assert(count.Sign() > 0, qt.IsTrue)
+
+	// invalid and oversized modes
+	_, err = ballot.CountBallots(ballot.Mode{NumFields: 9, MaxValue: 1, CostExponent: 1}, 0)
+	c.Assert(err, qt.ErrorMatches, "invalid ballot mode num_fields: .*")
+	_, err = ballot.CountBallots(ballot.Mode{NumFields: 8, MaxValue: 1 << 28, MinValueSum: 1 << 30, CostExponent: 1}, 0)
+	c.Assert(err, qt.ErrorMatches, "ballot mode too large to count: .*")
+	_, err = ballot.CountBallots(ballot.Mode{NumFields: 8, MaxValue: 1 << 20, MaxValueSum: 1, CostExponent: 1, CostFromWeight: true}, 1<<40)
+	c.Assert(err, qt.ErrorMatches, "ballot mode too large to count: .*")
+}
+
+func TestBallotSample(t *testing.T) {
+	c := qt.New(t)
+	r := testvectors.NewReader("ballot sample")
+	for _, tc := range []struct {
+		name    string
+		mode    ballot.Mode
+		weight  uint64
+		samples int
+	}{
+		{"approval", ballot.Mode{NumFields: 4, MaxValue: 1, MaxValueSum: 2, MinValueSum: 1, CostExponent: 1}, 0, 5000},
+		{"ranked", ballot.Mode{NumFields: 3, UniqueValues: true, MaxValue: 3, MinValue: 1, CostExponent: 1}, 0, 3000},
+		{"quadratic weighted", ballot.Mode{NumFields: 3, MaxValue: 3, MaxValueSum: 1, CostExponent: 2, CostFromWeight: true}, 5, 5000},
+		{"unique blocks", ballot.Mode{NumFields: 2, UniqueValues: true, MaxValue: 4, MinValueSum: 2, CostExponent: 1}, 0, 5000},
+	} {
+		c.Run(tc.name, func(c *qt.C) {
+			ballots, err := ballot.EnumerateBallots(tc.mode, tc.weight, 100)
+			c.Assert(err, qt.IsNil)
+			freq := make(map[string]int, len(ballots))
+			for _, b := range ballots {
+				freq[fmt.Sprint(b)] = 0
+			}
+			for range tc.samples {
+				fields, err := ballot.SampleBallot(tc.mode, tc.weight, r)
+				c.Assert(err, qt.IsNil)
+				c.Assert(ballot.CheckFields(tc.mode, fields, tc.weight), qt.IsNil)
+				key := fmt.Sprint(fields)
+				_, ok := freq[key]
+				c.Assert(ok, qt.IsTrue, qt.Commentf("unexpected ballot %s", key))
+				freq[key]++
+			}
+			// every ballot is drawn close to its expected frequency
+			expected := tc.samples / len(ballots)
+			for key, n := range freq {
+				c.Assert(n > expected*3/4 && n < expected*5/4, qt.IsTrue,
+					qt.Commentf("ballot %s drawn %d times, expected %d", key, n, expected))
+			}
+		})
+	}
+
+	// large spaces are sampled without enumerating them
+	mode := ballot.Mode{NumFields: 8, UniqueValues: true, MaxValue: 1 << 20, MinValueSum: 100, CostExponent: 2}
+	count, err := ballot.CountBallots(mode, 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(count.Cmp(new(big.Int).Lsh(big.NewInt(1), 150)) > 0, qt.IsTrue)
+	for range 10 {
+		fields, err := ballot.SampleBallot(mode, 0, r)
+		c.Assert(err, qt.IsNil)
+		c.Assert(ballot.CheckFields(mode, fields, 0), qt.IsNil)
+	}
+
+	_, err = ballot.SampleBallot(ballot.Mode{NumFields: 2, MaxValue: 3, MaxValueSum: 1, MinValueSum: 1, CostExponent: 2, CostFromWeight: true}, 0, r)
+	c.Assert(err, qt.ErrorMatches, "no ballot is accepted by the ballot mode")
+}
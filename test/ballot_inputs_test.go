@@ -0,0 +1,244 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/elgamal"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+// circomBase8 is the BabyJubJub generator of circomlibjs in TE coordinates.
+var circomBase8 = [2]string{
+	"5299619240641551281634865583518297030282874472190772894086521144482721001553",
+	"16950150798460657717958625567821834550301663161624707787222815936182638968203",
+}
+
+func TestElGamalEncrypt(t *testing.T) {
+	c := qt.New(t)
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+
+	// the public key is on the circom curve: 168700 x^2 + y^2 = 1 + 168696 x^2 y^2
+	c.Assert(onCircomCurve(pubX, pubY), qt.IsTrue)
+	x, y := elgamal.FromTEtoRTE(pubX, pubY)
+	x, y = elgamal.FromRTEtoTE(x, y)
+	c.Assert(x.Cmp(pubX), qt.Equals, 0)
+	c.Assert(y.Cmp(pubY), qt.Equals, 0)
+
+	// C1 = k·G, and with k = 1 it is the circomlibjs generator
+	c1, c2, err := elgamal.Encrypt(big.NewInt(0), pubX, pubY, big.NewInt(1))
+	c.Assert(err, qt.IsNil)
+	c.Assert([2]string{c1[0].String(), c1[1].String()}, qt.Equals, circomBase8)
+	c.Assert(onCircomCurve(c2[0], c2[1]), qt.IsTrue)
+
+	// C2 = k·Pub = priv·C1 when encrypting zero
+	c1, c2, err = elgamal.Encrypt(big.NewInt(0), pubX, pubY, big.NewInt(7))
+	c.Assert(err, qt.IsNil)
+	_, shared, err := elgamal.Encrypt(big.NewInt(0), c1[0], c1[1], priv)
+	c.Assert(err, qt.IsNil)
+	c.Assert(shared[0].Cmp(c2[0]), qt.Equals, 0)
+	c.Assert(shared[1].Cmp(c2[1]), qt.Equals, 0)
+
+	_, _, err = elgamal.Encrypt(big.NewInt(1), big.NewInt(1), big.NewInt(2), big.NewInt(3))
+	c.Assert(err, qt.ErrorMatches, "invalid public key: point is not on the BabyJubJub curve")
+}
+
+func TestBallotInputs(t *testing.T) {
+	c := qt.New(t)
+	_, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	b := &ballot.Ballot{
+		Mode:          testutils.SampleBallotMode,
+		ProcessID:     big.NewInt(101),
+		Address:       big.NewInt(202),
+		Weight:        3,
+		EncryptionKey: [2]*big.Int{pubX, pubY},
+		K:             big.NewInt(12345),
+		Fields:        []uint64{1, 2, 3, 4, 5},
+	}
+	inputs, err := ballot.BuildInputs(b)
+	c.Assert(err, qt.IsNil)
+	c.Assert(inputs.Fields, qt.DeepEquals, []uint64{1, 2, 3, 4, 5, 0, 0, 0})
+	c.Assert(inputs.Cipherfields, qt.HasLen, ballot.NFields)
+
+	// field i is encrypted with the (i+1)-th Poseidon hash of k
+	ks, err := ballot.DerivePoseidonChain(b.K, ballot.NFields)
+	c.Assert(err, qt.IsNil)
+	for i := range inputs.Fields {
+		c1, c2, err := elgamal.Encrypt(new(big.Int).SetUint64(inputs.Fields[i]), pubX, pubY, ks[i+1])
+		c.Assert(err, qt.IsNil)
+		c.Assert(ballot.StringifyCipherfields(inputs.Cipherfields[i:i+1]), qt.DeepEquals,
+			ballot.StringifyCipherfields([][2][2]*big.Int{{c1, c2}}))
+	}
+
+	// vote ID and inputs hash follow ballot_proof.circom
+	h, err := ballot.PoseidonHash(b.ProcessID, b.Address, b.K)
+	c.Assert(err, qt.IsNil)
+	c.Assert(inputs.VoteID.Cmp(ballot.TruncateTo160Bits(h)), qt.Equals, 0)
+	c.Assert(inputs.VoteID.BitLen() <= 160, qt.IsTrue)
+	list := []*big.Int{
+		b.ProcessID, big.NewInt(5), big.NewInt(1), big.NewInt(16), big.NewInt(0),
+		big.NewInt(1125), big.NewInt(5), big.NewInt(2), big.NewInt(0),
+		pubX, pubY, b.Address, inputs.VoteID,
+	}
+	for _, cf := range inputs.Cipherfields {
+		list = append(list, cf[0][0], cf[0][1], cf[1][0], cf[1][1])
+	}
+	list = append(list, big.NewInt(3))
+	c.Assert(list, qt.HasLen, 14+4*ballot.NFields)
+	var chunks []*big.Int
+	for i := 0; i < len(list); i += 16 {
+		h, err := ballot.PoseidonHash(list[i:min(i+16, len(list))]...)
+		c.Assert(err, qt.IsNil)
+		chunks = append(chunks, h)
+	}
+	want, err := ballot.PoseidonHash(chunks...)
+	c.Assert(err, qt.IsNil)
+	c.Assert(inputs.InputsHash.Cmp(want), qt.Equals, 0)
+
+	// the JSON encoding uses the circuit signal names
+	data, err := json.Marshal(inputs)
+	c.Assert(err, qt.IsNil)
+	var decoded map[string]any
+	c.Assert(json.Unmarshal(data, &decoded), qt.IsNil)
+	c.Assert(decoded["inputs_hash"], qt.Equals, want.String())
+	c.Assert(decoded["unique_values"], qt.Equals, float64(1))
+	c.Assert(decoded["cost_from_weight"], qt.Equals, float64(0))
+	c.Assert(decoded["cipherfields"], qt.HasLen, ballot.NFields)
+
+	// a random k is drawn when none is given
+	random := *b
+	random.K = nil
+	randomInputs, err := ballot.BuildInputs(&random)
+	c.Assert(err, qt.IsNil)
+	c.Assert(randomInputs.K.Cmp(b.K), qt.Not(qt.Equals), 0)
+	c.Assert(randomInputs.VoteID.Cmp(inputs.VoteID), qt.Not(qt.Equals), 0)
+}
+
+func TestBallotInputsErrors(t *testing.T) {
+	c := qt.New(t)
+	_, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	valid := ballot.Ballot{
+		Mode:          testutils.SampleBallotMode,
+		ProcessID:     big.NewInt(1),
+		Address:       big.NewInt(2),
+		EncryptionKey: [2]*big.Int{pubX, pubY},
+		Fields:        []uint64{1, 2, 3},
+	}
+	for _, tc := range []struct {
+		name   string
+		modify func(b *ballot.Ballot)
+		err    string
+	}{
+		{"missing process ID", func(b *ballot.Ballot) { b.ProcessID = nil }, "missing process ID"},
+		{"missing key", func(b *ballot.Ballot) { b.EncryptionKey[1] = nil }, "missing encryption key y"},
+		{"negative address", func(b *ballot.Ballot) { b.Address = big.NewInt(-2) }, "address is negative"},
+		{"negative k", func(b *ballot.Ballot) { b.K = big.NewInt(-1) }, "k is negative"},
+		{"too many fields", func(b *ballot.Ballot) { b.Fields = make([]uint64, ballot.NFields+1) }, "too many fields.*"},
+		{"field too wide", func(b *ballot.Ballot) { b.Fields = []uint64{1, 1 << elgamal.MsgBits} }, "field 1 value 4294967296 does not fit in the 32-bit messages of ElGamal"},
+		{"mode num fields", func(b *ballot.Ballot) { b.Mode.NumFields = ballot.NFields + 1 }, "invalid number of fields in ballot mode.*"},
+		{"key not on curve", func(b *ballot.Ballot) { b.EncryptionKey = [2]*big.Int{big.NewInt(1), big.NewInt(2)} }, "invalid encryption key: invalid public key: point is not on the BabyJubJub curve"},
+		{"identity key", func(b *ballot.Ballot) { b.EncryptionKey = [2]*big.Int{big.NewInt(0), big.NewInt(1)} }, "invalid encryption key: invalid public key: identity point"},
+		{"small order key", func(b *ballot.Ballot) {
+			b.EncryptionKey = [2]*big.Int{big.NewInt(0), new(big.Int).Sub(fr.Modulus(), big.NewInt(1))}
+		}, "invalid encryption key: invalid public key: point is not in the prime-order subgroup"},
+	} {
+		c.Run(tc.name, func(c *qt.C) {
+			b := valid
+			tc.modify(&b)
+			_, err := ballot.BuildInputs(&b)
+			c.Assert(err, qt.ErrorMatches, tc.err)
+		})
+	}
+
+	_, err = ballot.MultiHash(make([]*big.Int, ballot.MaxHashInputs+1))
+	c.Assert(err, qt.ErrorMatches, "invalid number of hash inputs.*")
+}
+
+// onCircomCurve reports whether (x, y) is on BabyJubJub in circom coordinates.
+func onCircomCurve(x, y *big.Int) bool {
+	p, _ := new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+	x2 := new(big.Int).Mul(x, x)
+	y2 := new(big.Int).Mul(y, y)
+	lhs := new(big.Int).Mul(big.NewInt(168700), x2)
+	lhs.Add(lhs, y2).Mod(lhs, p)
+	rhs := new(big.Int).Mul(big.NewInt(168696), x2)
+	rhs.Mul(rhs, y2).Add(rhs, big.NewInt(1)).Mod(rhs, p)
+	return lhs.Cmp(rhs) == 0
+}
+
+func TestBallotCircuitFields(t *testing.T) {
+	c := qt.New(t)
+	_, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ballot.MaxNFields, qt.Equals, 60)
+	c.Assert(ballot.HashInputsLen(ballot.MaxNFields) <= ballot.MaxHashInputs, qt.IsTrue)
+
+	for _, nFields := range []int{4, 16, 32, ballot.MaxNFields} {
+		circuit, err := ballot.NewCircuit(nFields)
+		c.Assert(err, qt.IsNil)
+		c.Assert(circuit.NFields(), qt.Equals, nFields)
+		fields := make([]uint64, nFields)
+		for i := range fields {
+			fields[i] = uint64(i + 1)
+		}
+		inputs, err := circuit.BuildInputs(&ballot.Ballot{
+			Mode: ballot.Mode{
+				NumFields:    nFields,
+				UniqueValues: true,
+				MaxValue:     uint64(nFields),
+				MinValue:     1,
+				CostExponent: 1,
+			},
+			ProcessID:     big.NewInt(1),
+			Address:       big.NewInt(2),
+			Weight:        1,
+			EncryptionKey: [2]*big.Int{pubX, pubY},
+			K:             big.NewInt(3),
+			Fields:        fields,
+		})
+		c.Assert(err, qt.IsNil)
+		c.Assert(inputs.Fields, qt.HasLen, nFields)
+		c.Assert(inputs.Cipherfields, qt.HasLen, nFields)
+		c.Assert(inputs.PublicSignals(), qt.DeepEquals, []string{"2", inputs.VoteID.String(), inputs.InputsHash.String()})
+		c.Assert(circuit.CheckFields(inputs.Mode, inputs.Fields, inputs.Weight), qt.IsNil)
+
+		// the default circuit cannot hold more than its fields
+		if nFields > ballot.NFields {
+			c.Assert(ballot.CheckFields(inputs.Mode, inputs.Fields, inputs.Weight), qt.ErrorMatches, "ballot violates num_fields: .*")
+		}
+	}
+
+	// the first eight fields of a larger circuit match the default circuit
+	// except for the padding cipherfields
+	circuit, err := ballot.NewCircuit(16)
+	c.Assert(err, qt.IsNil)
+	b := &ballot.Ballot{
+		Mode:          testutils.SampleBallotMode,
+		ProcessID:     big.NewInt(1),
+		Address:       big.NewInt(2),
+		EncryptionKey: [2]*big.Int{pubX, pubY},
+		K:             big.NewInt(3),
+		Fields:        []uint64{1, 2, 3, 4, 5},
+	}
+	small, err := ballot.BuildInputs(b)
+	c.Assert(err, qt.IsNil)
+	large, err := circuit.BuildInputs(b)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ballot.StringifyCipherfields(large.Cipherfields[:ballot.NFields]), qt.DeepEquals, ballot.StringifyCipherfields(small.Cipherfields))
+	c.Assert(large.VoteID.Cmp(small.VoteID), qt.Equals, 0)
+	c.Assert(large.InputsHash.Cmp(small.InputsHash), qt.Not(qt.Equals), 0)
+
+	for _, nFields := range []int{0, ballot.MaxNFields + 1} {
+		_, err := ballot.NewCircuit(nFields)
+		c.Assert(err, qt.ErrorMatches, "invalid number of circuit fields.*")
+	}
+	c.Assert(ballot.HashInputsLen(ballot.MaxNFields+1) > ballot.MaxHashInputs, qt.IsTrue)
+}
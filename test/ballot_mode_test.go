@@ -0,0 +1,147 @@
+package test
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/ballot/testvectors"
+)
+
+func TestBallotModeValidate(t *testing.T) {
+	c := qt.New(t)
+
+	// modes with a valid ballot are usable
+	for _, set := range testvectors.Sets {
+		circuit, err := ballot.NewCircuit(set.NFields)
+		c.Assert(err, qt.IsNil)
+		c.Assert(circuit.ValidateMode(set.Mode), qt.IsNil, qt.Commentf(set.Name))
+	}
+	for _, tc := range ballotCheckerCases {
+		if !tc.expectPass {
+			continue
+		}
+		mode := ballot.Mode{
+			NumFields:    tc.maxCount,
+			UniqueValues: tc.forceUnique,
+			MaxValue:     uint64(tc.maxValue),
+			MinValue:     uint64(tc.minValue),
+			MaxValueSum:  uint64(tc.maxTotalCost),
+			MinValueSum:  uint64(tc.minTotalCost),
+			CostExponent: tc.costExp,
+		}
+		c.Assert(ballot.ValidateMode(mode), qt.IsNil, qt.Commentf(tc.name))
+	}
+
+	valid := ballot.Mode{NumFields: 4, MaxValue: 10, MaxValueSum: 20, MinValueSum: 1, CostExponent: 2}
+	c.Assert(ballot.ValidateMode(valid), qt.IsNil)
+	for _, tc := range []struct {
+		name   string
+		change func(*ballot.Mode)
+		errs   []string
+	}{
+		{
+			name:   "too many fields",
+			change: func(m *ballot.Mode) { m.NumFields = ballot.NFields + 1 },
+			errs:   []string{"invalid ballot mode num_fields: num_fields 9 is greater than the 8 circuit fields"},
+		},
+		{
+			name:   "negative fields",
+			change: func(m *ballot.Mode) { m.NumFields = -1 },
+			errs:   []string{"invalid ballot mode num_fields: negative number of fields -1"},
+		},
+		{
+			name:   "negative exponent",
+			change: func(m *ballot.Mode) { m.CostExponent = -2 },
+			errs:   []string{"invalid ballot mode cost_exponent: negative exponent -2"},
+		},
+		{
+			name:   "max value above msg_bits",
+			change: func(m *ballot.Mode) { m.MaxValue = 1 << 32; m.MaxValueSum = 0 },
+			errs:   []string{"invalid ballot mode max_value: max_value 4294967296 does not fit in the 32-bit messages of ElGamal"},
+		},
+		{
+			name:   "min value above max value",
+			change: func(m *ballot.Mode) { m.MinValue = 11 },
+			errs:   []string{"invalid ballot mode min_value: min_value 11 is greater than max_value 10"},
+		},
+		{
+			name:   "min value sum above max value sum",
+			change: func(m *ballot.Mode) { m.MinValueSum = 21 },
+			errs:   []string{"invalid ballot mode min_value_sum: min_value_sum 21 is greater than max_value_sum 20"},
+		},
+		{
+			name:   "unique values out of range",
+			change: func(m *ballot.Mode) { m.UniqueValues = true; m.MinValue = 8 },
+			errs:   []string{"invalid ballot mode unique_values: 4 distinct values do not fit between 8 and 10"},
+		},
+		{
+			name:   "min value sum above the maximum cost",
+			change: func(m *ballot.Mode) { m.MaxValue = 2; m.MaxValueSum = 0; m.MinValueSum = 17 },
+			errs:   []string{"invalid ballot mode min_value_sum: min_value_sum 17 is greater than the maximum cost 16"},
+		},
+		{
+			name:   "max value sum below the minimum cost",
+			change: func(m *ballot.Mode) { m.UniqueValues = true; m.MinValue = 1 },
+			errs:   []string{"invalid ballot mode max_value_sum: max_value_sum 20 is less than the minimum cost 30"},
+		},
+		{
+			name:   "cost overflow",
+			change: func(m *ballot.Mode) { m.MaxValue = 1 << 31; m.CostExponent = 5 },
+			errs:   []string{"invalid ballot mode cost_exponent: the cost of 4 fields of value 2147483648 overflows the 128-bit comparators"},
+		},
+		{
+			name:   "cost overflow of every ballot",
+			change: func(m *ballot.Mode) { m.MinValue = 2; m.CostExponent = 200; m.MaxValueSum = 0 },
+			errs:   []string{"invalid ballot mode cost_exponent: the cost of every ballot overflows the 128-bit comparators"},
+		},
+		{
+			name: "all violations",
+			change: func(m *ballot.Mode) {
+				m.NumFields = 9
+				m.MaxValue = 1 << 33
+				m.MinValue = 1 << 34
+				m.MinValueSum = 30
+			},
+			errs: []string{
+				"invalid ballot mode num_fields: num_fields 9 is greater than the 8 circuit fields",
+				"invalid ballot mode max_value: max_value 8589934592 does not fit in the 32-bit messages of ElGamal",
+				"invalid ballot mode min_value: min_value 17179869184 does not fit in the 32-bit messages of ElGamal",
+				"invalid ballot mode min_value: min_value 17179869184 is greater than max_value 8589934592",
+				"invalid ballot mode min_value_sum: min_value_sum 30 is greater than max_value_sum 20",
+			},
+		},
+	} {
+		c.Run(tc.name, func(c *qt.C) {
+			mode := valid
+			tc.change(&mode)
+			err := ballot.ValidateMode(mode)
+			var errs ballot.ModeErrors
+			c.Assert(errors.As(err, &errs), qt.IsTrue, qt.Commentf("%v", err))
+			c.Assert(len(errs), qt.Equals, len(tc.errs))
+			for i := range errs {
+				c.Assert(errs[i].Error(), qt.Equals, tc.errs[i])
+			}
+		})
+	}
+
+	// the weight bounds the cost instead of max_value_sum
+	weighted := valid
+	weighted.CostFromWeight = true
+	weighted.MaxValueSum = 1
+	c.Assert(ballot.ValidateMode(weighted), qt.IsNil)
+
+	// num_fields is checked against the circuit
+	circuit, err := ballot.NewCircuit(16)
+	c.Assert(err, qt.IsNil)
+	wide := valid
+	wide.NumFields = 16
+	c.Assert(circuit.ValidateMode(wide), qt.IsNil)
+	c.Assert(ballot.ValidateMode(wide), qt.ErrorMatches, "invalid ballot mode num_fields: .*")
+
+	// presets are validated
+	_, err = ballot.Rating(3, 0, 1<<32)
+	c.Assert(err, qt.ErrorMatches, "invalid ballot mode max_value: .*")
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+package test
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot/nullifier"
+)
+
+func TestNullifierFileStorePartialWrite(t *testing.T) {
+	c := qt.New(t)
+	path := filepath.Join(t.TempDir(), "voteids.log")
+	store, err := nullifier.OpenFileStore(path)
+	c.Assert(err, qt.IsNil)
+	_, err = store.Record(big.NewInt(1), big.NewInt(2), big.NewInt(3))
+	c.Assert(err, qt.IsNil)
+	complete, err := os.ReadFile(path)
+	c.Assert(err, qt.IsNil)
+
+	// a file size limit lets only part of the next record reach the log,
+	// and the write fails with EFBIG (Go ignores SIGXFSZ)
+	var limit syscall.Rlimit
+	c.Assert(syscall.Getrlimit(syscall.RLIMIT_FSIZE, &limit), qt.IsNil)
+	restore := limit
+	limit.Cur = uint64(len(complete)) + 10
+	c.Assert(syscall.Setrlimit(syscall.RLIMIT_FSIZE, &limit), qt.IsNil)
+	_, err = store.Record(big.NewInt(1), big.NewInt(4), big.NewInt(5))
+	c.Assert(syscall.Setrlimit(syscall.RLIMIT_FSIZE, &restore), qt.IsNil)
+	c.Assert(err, qt.ErrorMatches, "failed to write vote ID log: .*")
+
+	// the fragment is rolled back, so the record can be retried and the log
+	// reopened
+	data, err := os.ReadFile(path)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(data), qt.Equals, string(complete))
+	ok, err := store.Contains(big.NewInt(1), big.NewInt(5))
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsFalse)
+	_, err = store.Record(big.NewInt(1), big.NewInt(4), big.NewInt(5))
+	c.Assert(err, qt.IsNil)
+	_, err = store.Record(big.NewInt(1), big.NewInt(6), big.NewInt(7))
+	c.Assert(err, qt.IsNil)
+	c.Assert(store.Close(), qt.IsNil)
+
+	store, err = nullifier.OpenFileStore(path)
+	c.Assert(err, qt.IsNil)
+	for _, voteID := range []int64{3, 5, 7} {
+		ok, err := store.Contains(big.NewInt(1), big.NewInt(voteID))
+		c.Assert(err, qt.IsNil)
+		c.Assert(ok, qt.IsTrue)
+	}
+	c.Assert(store.Close(), qt.IsNil)
+}
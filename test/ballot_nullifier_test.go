@@ -0,0 +1,157 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/ballot/nullifier"
+)
+
+func TestNullifierStores(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "voteids.log")
+	for _, tc := range []struct {
+		name  string
+		store func(c *qt.C) nullifier.Store
+	}{
+		{"memory", func(c *qt.C) nullifier.Store { return nullifier.NewMemoryStore() }},
+		{"file", func(c *qt.C) nullifier.Store {
+			s, err := nullifier.OpenFileStore(path)
+			c.Assert(err, qt.IsNil)
+			c.Cleanup(func() { c.Assert(s.Close(), qt.IsNil) })
+			return s
+		}},
+	} {
+		qt.New(t).Run(tc.name, func(c *qt.C) {
+			testNullifierStore(c, tc.store(c))
+		})
+	}
+
+	// the file store survives a restart
+	c := qt.New(t)
+	s, err := nullifier.OpenFileStore(path)
+	c.Assert(err, qt.IsNil)
+	defer func() { c.Assert(s.Close(), qt.IsNil) }()
+	pid, addr := big.NewInt(1), big.NewInt(100)
+	voteID, err := ballot.VoteID(pid, addr, big.NewInt(2))
+	c.Assert(err, qt.IsNil)
+	ok, err := s.Contains(pid, voteID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	latest, err := s.Latest(pid, addr)
+	c.Assert(err, qt.IsNil)
+	c.Assert(latest.Overwrites, qt.Equals, 1)
+	_, err = s.Record(pid, big.NewInt(101), voteID)
+	c.Assert(errors.Is(err, nullifier.ErrDuplicateVoteID), qt.IsTrue)
+}
+
+func testNullifierStore(c *qt.C, s nullifier.Store) {
+	pid, addr := big.NewInt(1), big.NewInt(100)
+	first, err := ballot.VoteID(pid, addr, big.NewInt(1))
+	c.Assert(err, qt.IsNil)
+	second, err := ballot.VoteID(pid, addr, big.NewInt(2))
+	c.Assert(err, qt.IsNil)
+
+	latest, err := s.Latest(pid, addr)
+	c.Assert(err, qt.IsNil)
+	c.Assert(latest, qt.IsNil)
+
+	vote, err := s.Record(pid, addr, first)
+	c.Assert(err, qt.IsNil)
+	c.Assert(vote.VoteID.Cmp(first), qt.Equals, 0)
+	c.Assert(vote.Previous, qt.IsNil)
+	c.Assert(vote.Overwrites, qt.Equals, 0)
+
+	// the same vote ID is rejected, in the same process only
+	_, err = s.Record(pid, addr, first)
+	c.Assert(err, qt.ErrorMatches, "duplicated vote ID: .*")
+	c.Assert(errors.Is(err, nullifier.ErrDuplicateVoteID), qt.IsTrue)
+	_, err = s.Record(big.NewInt(2), addr, first)
+	c.Assert(err, qt.IsNil)
+
+	// a new vote of the address overwrites the previous one
+	vote, err = s.Record(pid, addr, second)
+	c.Assert(err, qt.IsNil)
+	c.Assert(vote.Previous.Cmp(first), qt.Equals, 0)
+	c.Assert(vote.Overwrites, qt.Equals, 1)
+	latest, err = s.Latest(pid, addr)
+	c.Assert(err, qt.IsNil)
+	c.Assert(latest.VoteID.Cmp(second), qt.Equals, 0)
+	ok, err := s.Contains(pid, first)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	ok, err = s.Contains(big.NewInt(3), first)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsFalse)
+
+	_, err = s.Record(pid, addr, new(big.Int).Lsh(big.NewInt(1), 160))
+	c.Assert(err, qt.ErrorMatches, "invalid vote ID: more than 160 bits")
+	_, err = s.Record(pid, nil, first)
+	c.Assert(err, qt.ErrorMatches, "missing process ID, address or vote ID")
+
+	// concurrent submissions of the same vote ID: exactly one is accepted
+	pid = big.NewInt(4)
+	voteID, err := ballot.VoteID(pid, addr, big.NewInt(1))
+	c.Assert(err, qt.IsNil)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for range 16 {
+		wg.Go(func() {
+			if _, err := s.Record(pid, addr, voteID); err == nil {
+				mu.Lock()
+				accepted++
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	c.Assert(accepted, qt.Equals, 1)
+}
+
+func TestNullifierFileStoreCorrupted(t *testing.T) {
+	c := qt.New(t)
+	path := filepath.Join(t.TempDir(), "voteids.log")
+	c.Assert(os.WriteFile(path, []byte(`{"processId":"1","address":"2","voteId":"3"}`+"\n"+`{"processId":"1","address":"4","voteId":"3"}`+"\n"), 0o600), qt.IsNil)
+	_, err := nullifier.OpenFileStore(path)
+	c.Assert(err, qt.ErrorMatches, "invalid vote ID log line 2: duplicated vote ID: .*")
+	c.Assert(os.WriteFile(path, []byte("not json\n"), 0o600), qt.IsNil)
+	_, err = nullifier.OpenFileStore(path)
+	c.Assert(err, qt.ErrorMatches, "invalid vote ID log line 1: .*")
+	c.Assert(os.WriteFile(path, []byte(`{"processId":"1","address":"2","voteId":"3"}`+"\n"+"{\"processId\n"), 0o600), qt.IsNil)
+	_, err = nullifier.OpenFileStore(path)
+	c.Assert(err, qt.ErrorMatches, "invalid vote ID log line 2: .*")
+}
+
+func TestNullifierFileStoreTruncated(t *testing.T) {
+	c := qt.New(t)
+	path := filepath.Join(t.TempDir(), "voteids.log")
+	complete := `{"processId":"1","address":"2","voteId":"3"}` + "\n"
+	c.Assert(os.WriteFile(path, []byte(complete+`{"processId":"1","addr`), 0o600), qt.IsNil)
+
+	// the interrupted record is dropped and the log stays appendable
+	store, err := nullifier.OpenFileStore(path)
+	c.Assert(err, qt.IsNil)
+	ok, err := store.Contains(big.NewInt(1), big.NewInt(3))
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+	_, err = store.Record(big.NewInt(1), big.NewInt(4), big.NewInt(5))
+	c.Assert(err, qt.IsNil)
+	c.Assert(store.Close(), qt.IsNil)
+
+	data, err := os.ReadFile(path)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(data), qt.Equals, complete+`{"processId":"1","address":"4","voteId":"5"}`+"\n")
+	store, err = nullifier.OpenFileStore(path)
+	c.Assert(err, qt.IsNil)
+	vote, err := store.Latest(big.NewInt(1), big.NewInt(4))
+	c.Assert(err, qt.IsNil)
+	c.Assert(vote.VoteID.Int64(), qt.Equals, int64(5))
+	c.Assert(store.Close(), qt.IsNil)
+}
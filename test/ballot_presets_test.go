@@ -0,0 +1,136 @@
+package test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/ballot/testvectors"
+)
+
+func TestBallotModePresets(t *testing.T) {
+	c := qt.New(t)
+	mustMode := func(mode ballot.Mode, err error) ballot.Mode {
+		c.Assert(err, qt.IsNil)
+		return mode
+	}
+	for _, tc := range []struct {
+		name        string
+		mode        ballot.Mode
+		system      ballot.System
+		description string
+		valid       [][]uint64
+		invalid     [][]uint64
+		weight      uint64
+	}{
+		{
+			name:        "single choice",
+			mode:        mustMode(ballot.SingleChoice(4)),
+			system:      ballot.SystemSingleChoice,
+			description: "single choice: choose one of 4 candidates",
+			valid:       [][]uint64{{0, 0, 1, 0}, {1, 0, 0, 0}},
+			invalid:     [][]uint64{{0, 0, 0, 0}, {1, 1, 0, 0}, {0, 2, 0, 0}},
+		},
+		{
+			name:        "approval",
+			mode:        mustMode(ballot.Approval(6, 1, 3)),
+			system:      ballot.SystemApproval,
+			description: "approval: approve 1 to 3 of 6 candidates",
+			valid:       [][]uint64{{1, 0, 0, 0, 0, 0}, {1, 0, 1, 0, 1, 0}},
+			invalid:     [][]uint64{{0, 0, 0, 0, 0, 0}, {1, 1, 1, 1, 0, 0}, {2, 0, 0, 0, 0, 0}},
+		},
+		{
+			name:        "ranked choice",
+			mode:        mustMode(ballot.RankedChoice(3)),
+			system:      ballot.SystemRankedChoice,
+			description: "ranked choice: rank all 3 candidates from 1 to 3",
+			valid:       [][]uint64{{1, 2, 3}, {3, 1, 2}},
+			invalid:     [][]uint64{{1, 1, 2}, {0, 1, 2}, {1, 2, 4}},
+		},
+		{
+			name:        "rating",
+			mode:        mustMode(ballot.Rating(5, 1, 5)),
+			system:      ballot.SystemRating,
+			description: "rating: rate each of 5 candidates from 1 to 5",
+			valid:       [][]uint64{{5, 5, 5, 5, 5}, {1, 2, 3, 4, 5}},
+			invalid:     [][]uint64{{0, 1, 1, 1, 1}, {6, 1, 1, 1, 1}},
+		},
+		{
+			name:        "quadratic",
+			mode:        mustMode(ballot.Quadratic(3, 20)),
+			system:      ballot.SystemQuadratic,
+			description: "quadratic: spend up to 20 credits on 3 candidates, v votes cost v^2, at most 4 votes each",
+			valid:       [][]uint64{{4, 2, 0}, {2, 2, 2}},
+			invalid:     [][]uint64{{4, 2, 1}, {5, 0, 0}},
+		},
+		{
+			name:        "quadratic weighted",
+			mode:        mustMode(ballot.QuadraticWeighted(4, 10)),
+			system:      ballot.SystemQuadratic,
+			description: "quadratic: spend the voter weight as credits on 4 candidates, v votes cost v^2, at most 10 votes each",
+			valid:       [][]uint64{{5, 5, 7, 1}, {10, 0, 0, 0}},
+			invalid:     [][]uint64{{10, 1, 0, 0}, {11, 0, 0, 0}},
+			weight:      100,
+		},
+	} {
+		c.Run(tc.name, func(c *qt.C) {
+			c.Assert(ballot.Classify(tc.mode), qt.Equals, tc.system)
+			c.Assert(ballot.Describe(tc.mode), qt.Equals, tc.description)
+			for _, fields := range tc.valid {
+				c.Assert(ballot.CheckFields(tc.mode, fields, tc.weight), qt.IsNil, qt.Commentf("%v", fields))
+			}
+			for _, fields := range tc.invalid {
+				c.Assert(ballot.CheckFields(tc.mode, fields, tc.weight), qt.IsNotNil, qt.Commentf("%v", fields))
+			}
+		})
+	}
+
+	// the checker test cases describe the same systems
+	for _, tc := range ballotCheckerCases {
+		mode := ballot.Mode{
+			NumFields:    tc.maxCount,
+			UniqueValues: tc.forceUnique,
+			MaxValue:     uint64(tc.maxValue),
+			MinValue:     uint64(tc.minValue),
+			MaxValueSum:  uint64(tc.maxTotalCost),
+			MinValueSum:  uint64(tc.minTotalCost),
+			CostExponent: tc.costExp,
+		}
+		switch tc.name {
+		case "Approval voting – exactly 3 of 6 chosen – valid":
+			c.Assert(ballot.Classify(mode), qt.Equals, ballot.SystemApproval)
+			c.Assert(ballot.Describe(mode), qt.Equals, "approval: approve exactly 3 of 6 candidates")
+		case "Ranked‑choice voting – unique ranks 1..3 – valid":
+			c.Assert(ballot.Classify(mode), qt.Equals, ballot.SystemRankedChoice)
+		case "Quadratic voting cost within limit – valid":
+			c.Assert(ballot.Classify(mode), qt.Equals, ballot.SystemQuadratic)
+		}
+	}
+
+	// fixture sets
+	for name, system := range map[string]ballot.System{
+		"rating":             ballot.SystemCustom,
+		"approval":           ballot.SystemApproval,
+		"quadratic_weighted": ballot.SystemQuadratic,
+		"rating_16":          ballot.SystemRankedChoice,
+	} {
+		set, err := testvectors.Lookup(name)
+		c.Assert(err, qt.IsNil)
+		c.Assert(ballot.Classify(set.Mode), qt.Equals, system, qt.Commentf(name))
+	}
+	set, err := testvectors.Lookup("rating")
+	c.Assert(err, qt.IsNil)
+	c.Assert(ballot.Describe(set.Mode), qt.Equals,
+		"custom: 5 fields with values from 0 to 16, all distinct, sum of values^2 at most 1125, sum of values^2 at least 5")
+
+	// invalid parameters
+	_, err = ballot.SingleChoice(0)
+	c.Assert(err, qt.ErrorMatches, "invalid number of candidates: got 0, want 1 to 60")
+	_, err = ballot.Approval(3, 2, 4)
+	c.Assert(err, qt.ErrorMatches, "invalid number of picks: .*")
+	_, err = ballot.Rating(3, 5, 1)
+	c.Assert(err, qt.ErrorMatches, "invalid rating range: got 5 to 1")
+	_, err = ballot.Quadratic(3, 0)
+	c.Assert(err, qt.ErrorMatches, "invalid number of credits: got 0")
+}
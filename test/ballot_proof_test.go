@@ -15,7 +15,7 @@ func TestBallotProof(t *testing.T) {
 	vectors, err := testutils.BuildBallotVectors()
 	c.Assert(err, qt.IsNil)
 
-	inputBytes, err := json.MarshalIndent(vectors.InputsMap(), "", "  ")
+	inputBytes, err := json.MarshalIndent(vectors.Map(), "", "  ")
 	c.Assert(err, qt.IsNil)
 	if persist && testID != "" {
 		if outPath == "" {
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bn254"
+	"github.com/consensys/gnark/std/math/emulated"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+	"github.com/consensys/gnark/test"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/circom2gnark"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+// ballotRecursionCircuit verifies a ballot proof and exposes its public
+// inputs by name, checking the address against an outer public input.
+type ballotRecursionCircuit struct {
+	Proof        stdgroth16.Proof[sw_bn254.G1Affine, sw_bn254.G2Affine]
+	Ballot       circom2gnark.BallotPublicInputs                                              `gnark:",public"`
+	Address      emulated.Element[sw_bn254.ScalarField]                                       `gnark:",public"`
+	VerifyingKey stdgroth16.VerifyingKey[sw_bn254.G1Affine, sw_bn254.G2Affine, sw_bn254.GTEl] `gnark:"-"`
+}
+
+func (c *ballotRecursionCircuit) Define(api frontend.API) error {
+	field, err := emulated.NewField[sw_bn254.ScalarField](api)
+	if err != nil {
+		return err
+	}
+	field.AssertIsEqual(&c.Ballot.Address, &c.Address)
+	return circom2gnark.AssertBallotProofBN254(api, c.VerifyingKey, c.Proof, c.Ballot, stdgroth16.WithCompleteArithmetic())
+}
+
+func TestBallotRecursionBN254(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(circom2gnark.BallotProofSignals.Names(), qt.DeepEquals, []string{"address", "vote_id", "inputs_hash"})
+	idx, err := circom2gnark.BallotProofSignals.Index("vote_id")
+	c.Assert(err, qt.IsNil)
+	c.Assert(idx, qt.Equals, 1)
+	_, err = circom2gnark.BallotProofSignals.Index("weight")
+	c.Assert(err, qt.IsNotNil)
+
+	prover, err := testutils.NewSampleProver(ecc.BN254)
+	c.Assert(err, qt.IsNil)
+	vkeyBytes, err := prover.VerificationKey()
+	c.Assert(err, qt.IsNil)
+	proofJSON, pubJSON, err := prover.Prove(big.NewInt(3), big.NewInt(4), big.NewInt(5))
+	c.Assert(err, qt.IsNil)
+
+	placeholder, err := circom2gnark.Circom2BallotPlaceholderBN254(vkeyBytes, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(placeholder.Witness.Public, qt.HasLen, 3)
+	recProof, err := circom2gnark.Circom2BallotProofForRecursionBN254(vkeyBytes, proofJSON, pubJSON, true)
+	c.Assert(err, qt.IsNil)
+
+	circuit := &ballotRecursionCircuit{Proof: placeholder.Proof, VerifyingKey: placeholder.Vk}
+	assignment := &ballotRecursionCircuit{
+		Proof:   recProof.Proof,
+		Ballot:  recProof.PublicInputs,
+		Address: emulated.ValueOf[sw_bn254.ScalarField](3),
+	}
+	c.Assert(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()), qt.IsNil)
+
+	// named fields follow the public signals order
+	swapped := *assignment
+	swapped.Ballot.Address, swapped.Ballot.VoteID = recProof.PublicInputs.VoteID, recProof.PublicInputs.Address
+	swapped.Address = emulated.ValueOf[sw_bn254.ScalarField](4)
+	c.Assert(test.IsSolved(circuit, &swapped, ecc.BN254.ScalarField()), qt.IsNotNil)
+
+	// the ballot proof must have exactly three public signals
+	_, err = circom2gnark.BallotPublicInputsFromWitness(stdgroth16.Witness[sw_bn254.ScalarField]{
+		Public: make([]emulated.Element[sw_bn254.ScalarField], 2),
+	})
+	c.Assert(err, qt.ErrorMatches, "invalid number of ballot public inputs.*")
+}
+
+// TestBallotRecursionSnarkJS names the public signals of a real snarkjs
+// ballot_proof proof, whose public.json lists address, vote_id and
+// inputs_hash in declaration order.
+func TestBallotRecursionSnarkJS(t *testing.T) {
+	c := qt.New(t)
+	vectors, err := testutils.BuildBallotVectors()
+	c.Assert(err, qt.IsNil)
+	proofJSON, pubJSON, vkeyBytes, err := testutils.ProveBallot(vectors)
+	c.Assert(err, qt.IsNil)
+
+	var signals []string
+	c.Assert(json.Unmarshal([]byte(pubJSON), &signals), qt.IsNil)
+	c.Assert(signals, qt.DeepEquals, []string{vectors.Address.String(), vectors.VoteID.String(), vectors.InputsHash.String()})
+
+	placeholder, err := circom2gnark.Circom2BallotPlaceholderBN254(vkeyBytes, true)
+	c.Assert(err, qt.IsNil)
+	recProof, err := circom2gnark.Circom2BallotProofForRecursionBN254(vkeyBytes, proofJSON, pubJSON, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(recProof.PublicInputs, qt.DeepEquals, circom2gnark.BallotPublicInputs{
+		Address:    emulated.ValueOf[sw_bn254.ScalarField](vectors.Address),
+		VoteID:     emulated.ValueOf[sw_bn254.ScalarField](vectors.VoteID),
+		InputsHash: emulated.ValueOf[sw_bn254.ScalarField](vectors.InputsHash),
+	})
+
+	circuit := &ballotRecursionCircuit{Proof: placeholder.Proof, VerifyingKey: placeholder.Vk}
+	assignment := &ballotRecursionCircuit{
+		Proof:   recProof.Proof,
+		Ballot:  recProof.PublicInputs,
+		Address: emulated.ValueOf[sw_bn254.ScalarField](vectors.Address),
+	}
+	c.Assert(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()), qt.IsNil)
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+)
+
+// sequencerVectors are ballot inputs built from sequencer process data by the
+// JS BallotBuilder (js/scripts/sequencer-vectors.mjs), shared with the JS
+// package tests (js/test/vectors.test.ts).
+var sequencerVectors = filepath.Join("testdata", "ballot", "sequencer_vectors.json")
+
+type sequencerVector struct {
+	Name    string                      `json:"name"`
+	Process ballot.SequencerProcessData `json:"process"`
+	Fields  []uint64                    `json:"fields"`
+	Weight  uint64                      `json:"weight"`
+	K       string                      `json:"k"`
+	Inputs  json.RawMessage             `json:"inputs"`
+}
+
+func TestBallotInputsFromSequencer(t *testing.T) {
+	c := qt.New(t)
+	data, err := os.ReadFile(sequencerVectors)
+	c.Assert(err, qt.IsNil)
+	var vectors []sequencerVector
+	c.Assert(json.Unmarshal(data, &vectors), qt.IsNil)
+	c.Assert(vectors, qt.Not(qt.HasLen), 0)
+
+	for _, v := range vectors {
+		c.Run(v.Name, func(c *qt.C) {
+			k, ok := new(big.Int).SetString(v.K, 10)
+			c.Assert(ok, qt.IsTrue)
+			inputs, err := ballot.BuildInputsFromSequencer(&v.Process, v.Fields, v.Weight, k)
+			c.Assert(err, qt.IsNil)
+			got, err := json.Marshal(inputs)
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.JSONEquals, v.Inputs)
+		})
+	}
+}
+
+func TestSequencerProcessData(t *testing.T) {
+	c := qt.New(t)
+	dec, err := ballot.HexToDecimal("0xff")
+	c.Assert(err, qt.IsNil)
+	c.Assert(dec, qt.Equals, "255")
+	dec, err = ballot.HexToDecimal("A62E")
+	c.Assert(err, qt.IsNil)
+	c.Assert(dec, qt.Equals, "42542")
+	_, err = ballot.HexToDecimal("0x")
+	c.Assert(err, qt.ErrorMatches, `invalid hex string: "0x"`)
+	_, err = ballot.HexToDecimal("-1")
+	c.Assert(err, qt.IsNotNil)
+
+	mode, err := ballot.ParseBallotMode(ballot.SequencerBallotMode{
+		NumFields:      3,
+		UniqueValues:   true,
+		MaxValue:       "10",
+		MinValue:       "1",
+		MaxValueSum:    "0",
+		MinValueSum:    "2",
+		CostExponent:   2,
+		CostFromWeight: true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(mode, qt.Equals, ballot.Mode{
+		NumFields:      3,
+		UniqueValues:   true,
+		MaxValue:       10,
+		MinValue:       1,
+		MinValueSum:    2,
+		CostExponent:   2,
+		CostFromWeight: true,
+	})
+	_, err = ballot.ParseBallotMode(ballot.SequencerBallotMode{MaxValue: "1.5", MinValue: "0", MaxValueSum: "0", MinValueSum: "0"})
+	c.Assert(err, qt.ErrorMatches, "invalid ballot mode maxValue: .*")
+
+	data, err := os.ReadFile(sequencerVectors)
+	c.Assert(err, qt.IsNil)
+	var vectors []sequencerVector
+	c.Assert(json.Unmarshal(data, &vectors), qt.IsNil)
+	process := vectors[0].Process
+
+	// the encryption key is served in RTE coordinates
+	key, err := process.EncryptionKey()
+	c.Assert(err, qt.IsNil)
+	c.Assert(onCircomCurve(key[0], key[1]), qt.IsTrue)
+	c.Assert(key[1].String(), qt.Equals, process.PubKeyY)
+
+	_, err = ballot.BuildInputsFromSequencer(&process, []uint64{1}, 1, nil)
+	c.Assert(err, qt.ErrorMatches, "invalid number of fields: got 1, ballot mode has 2")
+	bad := process
+	bad.Address = "0xzz"
+	_, err = ballot.BuildInputsFromSequencer(&bad, []uint64{1, 2}, 1, nil)
+	c.Assert(err, qt.ErrorMatches, "invalid address: .*")
+
+	// bad encryption keys are caught before building any proof inputs
+	bad = process
+	bad.PubKeyX, bad.PubKeyY = "0", "1"
+	_, err = ballot.BuildInputsFromSequencer(&bad, []uint64{1, 2}, 1, nil)
+	c.Assert(err, qt.ErrorMatches, "invalid encryption key: identity point")
+	bad.PubKeyX = "1"
+	_, err = bad.EncryptionKey()
+	c.Assert(err, qt.ErrorMatches, "invalid encryption key: point is not on the BabyJubJub curve")
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/ballot/testvectors"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+// TestBallotFixtureSets checks that the fixture sets in artifacts/vectors match
+// their seeds. Regenerate them with go run ./cmd/ballotvectors.
+func TestBallotFixtureSets(t *testing.T) {
+	c := qt.New(t)
+	root, err := testutils.FindRepoRoot()
+	c.Assert(err, qt.IsNil)
+	dir := filepath.Join(root, testutils.ArtifactsDir, "vectors")
+
+	for _, set := range testvectors.Sets {
+		c.Run(set.Name, func(c *qt.C) {
+			v, err := testvectors.Generate(set)
+			c.Assert(err, qt.IsNil)
+			input, expected, err := v.Files()
+			c.Assert(err, qt.IsNil)
+
+			wantInput, err := os.ReadFile(filepath.Join(dir, testvectors.InputFile(set.Name)))
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(input), qt.Equals, string(wantInput))
+			wantExpected, err := os.ReadFile(filepath.Join(dir, testvectors.ExpectedFile(set.Name)))
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(expected), qt.Equals, string(wantExpected))
+
+			c.Assert(v.Inputs.Fields, qt.HasLen, set.NFields)
+			c.Assert(ballot.CheckFields(v.Inputs.Mode, v.Inputs.Fields, v.Inputs.Weight) == nil, qt.Equals, set.NFields <= ballot.NFields)
+		})
+	}
+}
+
+func TestBallotVectorsDeterminism(t *testing.T) {
+	c := qt.New(t)
+	set, err := testvectors.Lookup("rating")
+	c.Assert(err, qt.IsNil)
+
+	// the same seed gives the same key, process, address and k
+	v1, err := testvectors.GenerateFrom(set, testvectors.NewReader("seed"))
+	c.Assert(err, qt.IsNil)
+	v2, err := testvectors.GenerateFrom(set, testvectors.NewReader("seed"))
+	c.Assert(err, qt.IsNil)
+	in1, exp1, err := v1.Files()
+	c.Assert(err, qt.IsNil)
+	in2, exp2, err := v2.Files()
+	c.Assert(err, qt.IsNil)
+	c.Assert(bytes.Equal(in1, in2), qt.IsTrue)
+	c.Assert(bytes.Equal(exp1, exp2), qt.IsTrue)
+
+	v3, err := testvectors.GenerateFrom(set, testvectors.NewReader("other seed"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(v3.PrivateKey.Cmp(v1.PrivateKey), qt.Not(qt.Equals), 0)
+	c.Assert(v3.Inputs.K.Cmp(v1.Inputs.K), qt.Not(qt.Equals), 0)
+	c.Assert(v3.Inputs.InputsHash.Cmp(v1.Inputs.InputsHash), qt.Not(qt.Equals), 0)
+
+	// a set violating its own ballot mode is rejected
+	set.Fields = []uint64{1, 1, 2, 3, 4}
+	_, err = testvectors.Generate(set)
+	c.Assert(err, qt.ErrorMatches, "fixture set rating: field 1 violates unique_values: .*")
+
+	_, err = testvectors.Lookup("plurality")
+	c.Assert(err, qt.ErrorMatches, "unknown fixture set: plurality")
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+func TestParseProofSignals(t *testing.T) {
+	c := qt.New(t)
+	signals, err := ballot.ParseProofSignals([]string{"1", "2", "3"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(signals.Address.Int64(), qt.Equals, int64(1))
+	c.Assert(signals.VoteID.Int64(), qt.Equals, int64(2))
+	c.Assert(signals.InputsHash.Int64(), qt.Equals, int64(3))
+	c.Assert(signals.Strings(), qt.DeepEquals, []string{"1", "2", "3"})
+
+	_, err = ballot.ParseProofSignals([]string{"1", "2"})
+	c.Assert(err, qt.ErrorMatches, "invalid number of ballot public signals: got 2, want 3")
+	_, err = ballot.ParseProofSignals([]string{"0x1", "2", "3"})
+	c.Assert(err, qt.ErrorMatches, `invalid public signal address: "0x1"`)
+}
+
+func TestVerifyBallot(t *testing.T) {
+	c := qt.New(t)
+	vectors, err := testutils.BuildBallotVectors()
+	c.Assert(err, qt.IsNil)
+
+	// the sample circuit proves any three public signals, standing in for a
+	// ballot proof
+	prover, err := testutils.NewSampleProver(ecc.BN254)
+	c.Assert(err, qt.IsNil)
+	vkey, err := prover.VerificationKey()
+	c.Assert(err, qt.IsNil)
+	proof, pubJSON, err := prover.Prove(vectors.Address, vectors.VoteID, vectors.InputsHash)
+	c.Assert(err, qt.IsNil)
+	var signals []string
+	c.Assert(json.Unmarshal([]byte(pubJSON), &signals), qt.IsNil)
+	c.Assert(signals, qt.DeepEquals, vectors.PublicSignals())
+
+	c.Assert(ballot.VerifyBallot(vkey, vectors.Submission(proof, signals)), qt.IsNil)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(s *ballot.Submission)
+		err    string
+	}{
+		{"weight", func(s *ballot.Submission) { s.Weight++ }, "public signal inputs_hash does not match the ballot.*"},
+		{"ballot mode", func(s *ballot.Submission) { s.Mode.MaxValue++ }, "public signal inputs_hash does not match the ballot.*"},
+		{"process ID", func(s *ballot.Submission) { s.ProcessID = big.NewInt(7) }, "public signal inputs_hash does not match the ballot.*"},
+		{"swapped cipherfields", func(s *ballot.Submission) {
+			s.Cipherfields = append([][2][2]*big.Int{}, s.Cipherfields...)
+			s.Cipherfields[0], s.Cipherfields[1] = s.Cipherfields[1], s.Cipherfields[0]
+		}, "public signal inputs_hash does not match the ballot.*"},
+		{"address", func(s *ballot.Submission) { s.Address = new(big.Int).Add(s.Address, big.NewInt(1)) }, "public signal address does not match the ballot.*"},
+		{"signals", func(s *ballot.Submission) {
+			s.PublicSignals = []string{s.PublicSignals[2], s.PublicSignals[0], s.PublicSignals[1]}
+		}, "public signal address does not match the ballot.*"},
+		{"inputs hash signal", func(s *ballot.Submission) {
+			s.PublicSignals = []string{s.PublicSignals[0], s.PublicSignals[1], "1"}
+		}, "public signal inputs_hash does not match the ballot.*"},
+		{"proof", func(s *ballot.Submission) {
+			other, _, err := prover.Prove(big.NewInt(1), big.NewInt(2), big.NewInt(3))
+			c.Assert(err, qt.IsNil)
+			s.Proof = other
+		}, "invalid ballot proof.*"},
+		{"cipherfields", func(s *ballot.Submission) { s.Cipherfields = s.Cipherfields[:3] }, "invalid number of cipherfields: got 3, want 8"},
+		{"cipherfield point", func(s *ballot.Submission) {
+			s.Cipherfields = append([][2][2]*big.Int{}, s.Cipherfields...)
+			s.Cipherfields[2] = [2][2]*big.Int{s.Cipherfields[2][0], {big.NewInt(1), big.NewInt(2)}}
+		}, "invalid cipherfield 2: C2 is not on the BabyJubJub curve"},
+		{"encryption key", func(s *ballot.Submission) { s.EncryptionKey = [2]*big.Int{big.NewInt(0), big.NewInt(1)} }, "invalid encryption key: .*"},
+		{"vote ID", func(s *ballot.Submission) { s.VoteID = new(big.Int).Lsh(big.NewInt(1), 160) }, "invalid vote ID: more than 160 bits"},
+	} {
+		c.Run(tc.name, func(c *qt.C) {
+			s := vectors.Submission(proof, signals)
+			tc.modify(s)
+			c.Assert(ballot.VerifyBallot(vkey, s), qt.ErrorMatches, tc.err)
+		})
+	}
+
+	// a rerandomized ballot no longer matches its proof
+	rerandomized, _, err := ballot.RerandomizeCipherfields(vectors.Cipherfields, vectors.EncryptionKey, nil)
+	c.Assert(err, qt.IsNil)
+	s := vectors.Submission(proof, signals)
+	s.Cipherfields = rerandomized
+	c.Assert(ballot.VerifyBallot(vkey, s), qt.ErrorMatches, "public signal inputs_hash does not match the ballot.*")
+}
+
+// TestVerifyBallotSnarkJS verifies a real ballot_proof proof generated by
+// snarkjs, as TestBallotProof does, with its proof.json and public.json.
+func TestVerifyBallotSnarkJS(t *testing.T) {
+	c := qt.New(t)
+	vectors, err := testutils.BuildBallotVectors()
+	c.Assert(err, qt.IsNil)
+	proof, pubJSON, vkey, err := testutils.ProveBallot(vectors)
+	c.Assert(err, qt.IsNil)
+	var signals []string
+	c.Assert(json.Unmarshal([]byte(pubJSON), &signals), qt.IsNil)
+
+	parsed, err := ballot.ParseProofSignals(signals)
+	c.Assert(err, qt.IsNil)
+	c.Assert(parsed.Address.Cmp(vectors.Address), qt.Equals, 0)
+	c.Assert(parsed.VoteID.Cmp(vectors.VoteID), qt.Equals, 0)
+	c.Assert(parsed.InputsHash.Cmp(vectors.InputsHash), qt.Equals, 0)
+	c.Assert(parsed.Strings(), qt.DeepEquals, signals)
+	c.Assert(vectors.PublicSignals(), qt.DeepEquals, signals)
+
+	c.Assert(ballot.VerifyBallot(vkey, vectors.Submission(proof, signals)), qt.IsNil)
+	s := vectors.Submission(proof, signals)
+	s.Weight++
+	c.Assert(ballot.VerifyBallot(vkey, s), qt.ErrorMatches, "public signal inputs_hash does not match the ballot.*")
+}
@@ -0,0 +1,85 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/circom2gnark"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+func TestCircomProofBinaryBN254(t *testing.T) {
+	c := qt.New(t)
+	c.Run("sample", func(c *qt.C) {
+		prover, err := testutils.NewSampleProver(ecc.BN254)
+		c.Assert(err, qt.IsNil)
+		vkeyBytes, err := prover.VerificationKey()
+		c.Assert(err, qt.IsNil)
+		proofJSON, pubJSON, err := prover.Prove(big.NewInt(5), big.NewInt(6), big.NewInt(7))
+		c.Assert(err, qt.IsNil)
+		checkCircomProofBinaryBN254(c, vkeyBytes, proofJSON, pubJSON)
+	})
+	c.Run("snarkjs", func(c *qt.C) {
+		fixture, err := testutils.ReadSnarkJSFixture("groth16")
+		c.Assert(err, qt.IsNil)
+		checkCircomProofBinaryBN254(c, fixture.Vkey, fixture.Proof, fixture.Public)
+	})
+}
+
+// checkCircomProofBinaryBN254 round trips a Groth16 proof through both binary
+// encodings and verifies the decoded proof.
+func checkCircomProofBinaryBN254(c *qt.C, vkeyBytes []byte, proofJSON, pubJSON string) {
+	proof, pubSignals, err := circom2gnark.UnmarshalCircom(proofJSON, pubJSON)
+	c.Assert(err, qt.IsNil)
+
+	for _, tc := range []struct {
+		name       string
+		compressed bool
+		size       int
+	}{
+		{"compressed", true, circom2gnark.CircomProofCompressedSizeBN254},
+		{"uncompressed", false, circom2gnark.CircomProofUncompressedSizeBN254},
+	} {
+		c.Run(tc.name, func(c *qt.C) {
+			data, err := proof.MarshalBinaryBN254(tc.compressed)
+			c.Assert(err, qt.IsNil)
+			c.Assert(data, qt.HasLen, tc.size)
+
+			decoded, err := circom2gnark.UnmarshalCircomProofBinaryBN254(data)
+			c.Assert(err, qt.IsNil)
+			c.Assert(decoded, qt.DeepEquals, proof)
+
+			gnarkProof, err := decoded.ToGnarkProofBN254(mustVkey(c, vkeyBytes), pubSignals)
+			c.Assert(err, qt.IsNil)
+			valid, err := gnarkProof.Verify()
+			c.Assert(err, qt.IsNil)
+			c.Assert(valid, qt.IsTrue)
+		})
+	}
+
+	// the uncompressed form matches the Solidity verifier proof argument
+	data, err := proof.MarshalBinaryBN254(false)
+	c.Assert(err, qt.IsNil)
+	calldata, err := proof.ToSolidityCalldataBN254(pubSignals)
+	c.Assert(err, qt.IsNil)
+	for i, word := range calldata.Proof {
+		c.Assert(new(big.Int).SetBytes(data[i*32:(i+1)*32]).Cmp(word), qt.Equals, 0)
+	}
+
+	_, err = circom2gnark.UnmarshalCircomProofBinaryBN254(make([]byte, 100))
+	c.Assert(err, qt.ErrorMatches, "invalid binary proof size.*")
+	corrupted, err := proof.MarshalBinaryBN254(false)
+	c.Assert(err, qt.IsNil)
+	corrupted[63] ^= 1
+	_, err = circom2gnark.UnmarshalCircomProofBinaryBN254(corrupted)
+	c.Assert(err, qt.ErrorMatches, "failed to decode PiA.*")
+}
+
+func mustVkey(c *qt.C, vkeyBytes []byte) *circom2gnark.CircomVerificationKey {
+	vk, err := circom2gnark.UnmarshalCircomVerificationKeyJSON(vkeyBytes)
+	c.Assert(err, qt.IsNil)
+	return vk
+}
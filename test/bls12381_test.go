@@ -0,0 +1,92 @@
+package test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_bls12381"
+	"github.com/consensys/gnark/std/math/emulated"
+	stdgroth16 "github.com/consensys/gnark/std/recursion/groth16"
+	"github.com/consensys/gnark/test"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/circom2gnark"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+// bls12381RecursionCircuit verifies a BLS12-381 Groth16 proof inside BN254.
+type bls12381RecursionCircuit struct {
+	Proof        stdgroth16.Proof[sw_bls12381.G1Affine, sw_bls12381.G2Affine]
+	PublicInputs [3]emulated.Element[sw_bls12381.ScalarField]                                          `gnark:",public"`
+	VerifyingKey stdgroth16.VerifyingKey[sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl] `gnark:"-"`
+}
+
+func (c *bls12381RecursionCircuit) Define(api frontend.API) error {
+	verifier, err := stdgroth16.NewVerifier[sw_bls12381.ScalarField, sw_bls12381.G1Affine, sw_bls12381.G2Affine, sw_bls12381.GTEl](api)
+	if err != nil {
+		return err
+	}
+	witness := stdgroth16.Witness[sw_bls12381.ScalarField]{Public: c.PublicInputs[:]}
+	if err := verifier.AssertProof(c.VerifyingKey, c.Proof, witness, stdgroth16.WithCompleteArithmetic()); err != nil {
+		return fmt.Errorf("proof: %w", err)
+	}
+	return nil
+}
+
+func TestCircomProofBLS12381(t *testing.T) {
+	c := qt.New(t)
+	c.Run("sample", func(c *qt.C) {
+		prover, err := testutils.NewSampleProver(ecc.BLS12_381)
+		c.Assert(err, qt.IsNil)
+		vkeyBytes, err := prover.VerificationKey()
+		c.Assert(err, qt.IsNil)
+		proofJSON, pubJSON, err := prover.Prove(big.NewInt(3), big.NewInt(4), big.NewInt(5))
+		c.Assert(err, qt.IsNil)
+		checkCircomProofBLS12381(c, vkeyBytes, proofJSON, pubJSON)
+	})
+	c.Run("snarkjs", func(c *qt.C) {
+		fixture, err := testutils.ReadSnarkJSFixture("groth16_bls12381")
+		c.Assert(err, qt.IsNil)
+		checkCircomProofBLS12381(c, fixture.Vkey, fixture.Proof, fixture.Public)
+	})
+}
+
+// checkCircomProofBLS12381 verifies a BLS12-381 Groth16 proof natively and
+// inside a BN254 recursion circuit.
+func checkCircomProofBLS12381(c *qt.C, vkeyBytes []byte, proofJSON, pubJSON string) {
+	pubSignals, err := circom2gnark.UnmarshalCircomPublicSignalsJSON([]byte(pubJSON))
+	c.Assert(err, qt.IsNil)
+
+	vk, err := circom2gnark.UnmarshalCircomVerificationKeyJSON(vkeyBytes)
+	c.Assert(err, qt.IsNil)
+	curve, err := vk.CurveID()
+	c.Assert(err, qt.IsNil)
+	c.Assert(curve, qt.Equals, ecc.BLS12_381)
+
+	ok, err := circom2gnark.VerifyCircomProof(vkeyBytes, proofJSON, pubSignals)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+
+	// 48-byte coordinates do not fit the BN254 conversion
+	_, err = circom2gnark.VerifyCircomProofBN254(vkeyBytes, proofJSON, pubSignals)
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	badSignals := append([]string{"6"}, pubSignals[1:]...)
+	ok, err = circom2gnark.VerifyCircomProof(vkeyBytes, proofJSON, badSignals)
+	c.Assert(err, qt.Not(qt.IsNil))
+	c.Assert(ok, qt.IsFalse)
+
+	placeholder, err := circom2gnark.Circom2GnarkPlaceholderBLS12381(vkeyBytes, len(pubSignals), true)
+	c.Assert(err, qt.IsNil)
+	recProof, err := circom2gnark.Circom2GnarkProofForRecursionBLS12381(vkeyBytes, proofJSON, pubJSON, true)
+	c.Assert(err, qt.IsNil)
+
+	circuit := &bls12381RecursionCircuit{Proof: placeholder.Proof, VerifyingKey: placeholder.Vk}
+	assignment := &bls12381RecursionCircuit{Proof: recProof.Proof, VerifyingKey: placeholder.Vk}
+	copy(assignment.PublicInputs[:], recProof.PublicInputs.Public)
+	err = test.IsSolved(circuit, assignment, ecc.BN254.ScalarField())
+	c.Assert(err, qt.IsNil)
+}
@@ -0,0 +1,100 @@
+package test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+// packedPointVectors holds BabyJubJub points in TE coordinates with their
+// circomlibjs packPoint encoding, written by js/scripts/packed-points.mjs and
+// shared with the JS package tests.
+const packedPointVectors = "testdata/elgamal/packed_points.json"
+
+type packedPointVector struct {
+	Point  [2]string `json:"point"`
+	Packed string    `json:"packed"`
+}
+
+func TestElGamalPackPoint(t *testing.T) {
+	c := qt.New(t)
+	data, err := os.ReadFile(packedPointVectors)
+	c.Assert(err, qt.IsNil)
+	var vectors []packedPointVector
+	c.Assert(json.Unmarshal(data, &vectors), qt.IsNil)
+	c.Assert(vectors, qt.Not(qt.HasLen), 0)
+
+	// the vectors include the base point and a point with x > (p-1)/2
+	halfP := new(big.Int).Rsh(fr.Modulus(), 1)
+	var base, signed bool
+	for _, v := range vectors {
+		x, _ := new(big.Int).SetString(v.Point[0], 10)
+		base = base || v.Point == [2]string{
+			"995203441582195749578291179787384436505546430278305826713579947235728471134",
+			"5472060717959818805561601436314318772137091100104008585924551046643952123905",
+		}
+		signed = signed || x.Cmp(halfP) > 0
+	}
+	c.Assert(base, qt.IsTrue)
+	c.Assert(signed, qt.IsTrue)
+
+	for _, v := range vectors {
+		x, _ := new(big.Int).SetString(v.Point[0], 10)
+		y, _ := new(big.Int).SetString(v.Point[1], 10)
+		packed, err := elgamal.PackPoint(x, y)
+		c.Assert(err, qt.IsNil)
+		c.Assert(hex.EncodeToString(packed[:]), qt.Equals, v.Packed)
+
+		raw, err := hex.DecodeString(v.Packed)
+		c.Assert(err, qt.IsNil)
+		ux, uy, err := elgamal.UnpackPoint(raw)
+		c.Assert(err, qt.IsNil)
+		c.Assert(ux.String(), qt.Equals, v.Point[0])
+		c.Assert(uy.String(), qt.Equals, v.Point[1])
+	}
+
+	// random keys and ciphertexts round trip
+	_, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	c1, c2, err := elgamal.Encrypt(big.NewInt(9), pubX, pubY, big.NewInt(31))
+	c.Assert(err, qt.IsNil)
+	ct := elgamal.NewCiphertext(c1, c2)
+	packed, err := ct.Pack()
+	c.Assert(err, qt.IsNil)
+	unpacked, err := elgamal.UnpackCiphertext(packed[:])
+	c.Assert(err, qt.IsNil)
+	c.Assert(unpacked.Equal(ct), qt.IsTrue)
+	key, err := (&elgamal.PointTE{X: pubX, Y: pubY}).Pack()
+	c.Assert(err, qt.IsNil)
+	unpackedKey, err := elgamal.UnpackPointTE(key[:])
+	c.Assert(err, qt.IsNil)
+	c.Assert(unpackedKey.Validate(), qt.IsNil)
+	c.Assert(unpackedKey.Equal(&elgamal.PointTE{X: pubX, Y: pubY}), qt.IsTrue)
+
+	// malformed encodings
+	_, err = elgamal.UnpackPointTE(key[:31])
+	c.Assert(err, qt.ErrorMatches, "invalid packed point size: got 31, want 32")
+	var bad [32]byte
+	new(big.Int).Sub(fr.Modulus(), big.NewInt(0)).FillBytes(bad[:])
+	for i, j := 0, 31; i < j; i, j = i+1, j-1 {
+		bad[i], bad[j] = bad[j], bad[i]
+	}
+	_, err = elgamal.UnpackPointTE(bad[:])
+	c.Assert(err, qt.ErrorMatches, "invalid packed point: y out of field range")
+	bad = [32]byte{2}
+	_, err = elgamal.UnpackPointTE(bad[:])
+	c.Assert(err, qt.ErrorMatches, "invalid packed point: not on the BabyJubJub curve")
+	bad = [32]byte{1}
+	bad[31] = 0x80
+	_, err = elgamal.UnpackPointTE(bad[:])
+	c.Assert(err, qt.ErrorMatches, "invalid packed point: sign set for x = 0")
+	_, err = elgamal.UnpackCiphertext(append(key[:], bad[:]...))
+	c.Assert(err, qt.ErrorMatches, "invalid C2: .*")
+}
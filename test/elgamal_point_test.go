@@ -0,0 +1,74 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+func TestElGamalPoints(t *testing.T) {
+	c := qt.New(t)
+	base8, err := elgamal.ParsePointTE(circomBase8[0], circomBase8[1])
+	c.Assert(err, qt.IsNil)
+	c.Assert(base8.IsOnCurve(), qt.IsTrue)
+	c.Assert(base8.InSubgroup(), qt.IsTrue)
+	c.Assert(base8.IsIdentity(), qt.IsFalse)
+
+	// conversions round trip and keep the point valid
+	rte := base8.RTE()
+	c.Assert(rte.Validate(), qt.IsNil)
+	c.Assert(rte.TE().Equal(base8), qt.IsTrue)
+	c.Assert(rte.Equal(base8.RTE()), qt.IsTrue)
+	hexX := "0x" + rte.X.Text(16)
+	parsed, err := elgamal.ParsePointRTE(hexX, rte.Y.String())
+	c.Assert(err, qt.IsNil)
+	c.Assert(parsed.Equal(rte), qt.IsTrue)
+
+	// JSON as circom encodes points
+	data, err := json.Marshal(base8)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(data), qt.Equals, `["`+circomBase8[0]+`","`+circomBase8[1]+`"]`)
+	var decoded elgamal.PointTE
+	c.Assert(json.Unmarshal(data, &decoded), qt.IsNil)
+	c.Assert(decoded.Equal(base8), qt.IsTrue)
+	c.Assert(json.Unmarshal([]byte(`["0","1"]`), &decoded), qt.ErrorMatches, "identity point")
+	c.Assert(json.Unmarshal([]byte(`["1"]`), &decoded), qt.ErrorMatches, "invalid point coordinate: \"\"")
+
+	p := fr.Modulus()
+	for _, tc := range []struct {
+		name  string
+		point *elgamal.PointTE
+		err   string
+	}{
+		{"missing", &elgamal.PointTE{X: big.NewInt(0)}, "missing point coordinate"},
+		{"not on curve", &elgamal.PointTE{X: big.NewInt(1), Y: big.NewInt(2)}, "point is not on the BabyJubJub curve"},
+		{"identity", &elgamal.PointTE{X: big.NewInt(0), Y: big.NewInt(1)}, "identity point"},
+		{"order two", &elgamal.PointTE{X: big.NewInt(0), Y: new(big.Int).Sub(p, big.NewInt(1))}, "point is not in the prime-order subgroup"},
+		{"not canonical", &elgamal.PointTE{X: new(big.Int).Add(base8.X, p), Y: base8.Y}, "point coordinate out of field range"},
+		{"negative", &elgamal.PointTE{X: new(big.Int).Neg(base8.X), Y: base8.Y}, "point coordinate out of field range"},
+	} {
+		c.Run(tc.name, func(c *qt.C) {
+			c.Assert(tc.point.Validate(), qt.ErrorMatches, tc.err)
+		})
+	}
+
+	// adding the point of order two to a subgroup point leaves the subgroup
+	order2 := elgamal.PointTE{X: big.NewInt(0), Y: new(big.Int).Sub(p, big.NewInt(1))}
+	c.Assert(order2.IsOnCurve(), qt.IsTrue)
+	c1, c2, err := elgamal.Encrypt(big.NewInt(0), base8.X, base8.Y, big.NewInt(1))
+	c.Assert(err, qt.IsNil)
+	sum, err := elgamal.NewCiphertext(c1, c2).Add(elgamal.NewCiphertext(c1, [2]*big.Int{order2.X, order2.Y}))
+	c.Assert(err, qt.IsNil)
+	torsion := elgamal.PointTE{X: sum.C2[0], Y: sum.C2[1]}
+	c.Assert(torsion.IsOnCurve(), qt.IsTrue)
+	c.Assert(torsion.InSubgroup(), qt.IsFalse)
+	c.Assert(elgamal.ValidatePublicKey(torsion.X, torsion.Y), qt.ErrorMatches, "invalid public key: point is not in the prime-order subgroup")
+
+	_, err = elgamal.ParsePointTE("12a", "1")
+	c.Assert(err, qt.ErrorMatches, "invalid point coordinate: \"12a\"")
+}
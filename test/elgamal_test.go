@@ -0,0 +1,216 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot/testvectors"
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+func TestElGamalDecrypt(t *testing.T) {
+	c := qt.New(t)
+	table, err := elgamal.NewDLogTable(elgamal.MsgBits)
+	c.Assert(err, qt.IsNil)
+	c.Assert(table.Bits(), qt.Equals, 32)
+
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	for _, m := range []uint64{0, 1, 5, 65535, 65536, 123456789, 1<<32 - 1} {
+		c1, c2, err := elgamal.Encrypt(new(big.Int).SetUint64(m), pubX, pubY, big.NewInt(int64(m)+42))
+		c.Assert(err, qt.IsNil)
+		got, err := elgamal.Decrypt(priv, c1, c2, table)
+		c.Assert(err, qt.IsNil)
+		c.Assert(got, qt.Equals, m)
+
+		// M = m·G
+		point, err := elgamal.DecryptPoint(priv, c1, c2)
+		c.Assert(err, qt.IsNil)
+		_, want, err := elgamal.Encrypt(new(big.Int).SetUint64(m), pubX, pubY, big.NewInt(0))
+		c.Assert(err, qt.IsNil)
+		c.Assert(point[0].Cmp(want[0]), qt.Equals, 0)
+		c.Assert(point[1].Cmp(want[1]), qt.Equals, 0)
+	}
+
+	// messages beyond msg_bits are not recovered
+	c1, c2, err := elgamal.Encrypt(big.NewInt(1<<32), pubX, pubY, big.NewInt(7))
+	c.Assert(err, qt.IsNil)
+	_, err = elgamal.Decrypt(priv, c1, c2, table)
+	c.Assert(err, qt.ErrorMatches, "message out of range: not below 2\\^32")
+
+	_, err = elgamal.Decrypt(priv, [2]*big.Int{big.NewInt(1), big.NewInt(2)}, c2, table)
+	c.Assert(err, qt.ErrorMatches, "invalid C1: point is not on the BabyJubJub curve")
+	_, err = elgamal.Decrypt(priv, c1, c2, nil)
+	c.Assert(err, qt.ErrorMatches, "missing discrete log table")
+
+	// the fixture sets decrypt to their fields
+	for _, set := range testvectors.Sets {
+		v, err := testvectors.Generate(set)
+		c.Assert(err, qt.IsNil)
+		for i, cf := range v.Inputs.Cipherfields {
+			got, err := elgamal.Decrypt(v.PrivateKey, cf[0], cf[1], table)
+			c.Assert(err, qt.IsNil)
+			c.Assert(got, qt.Equals, v.Inputs.Fields[i])
+		}
+	}
+}
+
+func TestDLogTableBinary(t *testing.T) {
+	c := qt.New(t)
+	table, err := elgamal.NewDLogTable(20)
+	c.Assert(err, qt.IsNil)
+	data, err := table.MarshalBinary()
+	c.Assert(err, qt.IsNil)
+	c.Assert(data, qt.HasLen, 6+12*1024)
+
+	loaded := &elgamal.DLogTable{}
+	c.Assert(loaded.UnmarshalBinary(data), qt.IsNil)
+	c.Assert(loaded.Bits(), qt.Equals, 20)
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	c1, c2, err := elgamal.Encrypt(big.NewInt(1<<20-3), pubX, pubY, big.NewInt(99))
+	c.Assert(err, qt.IsNil)
+	got, err := elgamal.Decrypt(priv, c1, c2, loaded)
+	c.Assert(err, qt.IsNil)
+	c.Assert(got, qt.Equals, uint64(1<<20-3))
+
+	// corrupted tables are rejected
+	c.Assert(loaded.UnmarshalBinary(data[:len(data)-1]), qt.ErrorMatches, "invalid discrete log table size.*")
+	bad := append([]byte{}, data...)
+	bad[0] = 9
+	c.Assert(loaded.UnmarshalBinary(bad), qt.ErrorMatches, "unsupported discrete log table version: 9")
+	bad = append([]byte{}, data...)
+	copy(bad[6:14], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	c.Assert(loaded.UnmarshalBinary(bad), qt.ErrorMatches, "invalid discrete log table: entries not sorted")
+
+	_, err = elgamal.NewDLogTable(elgamal.MaxDLogBits + 1)
+	c.Assert(err, qt.ErrorMatches, "invalid message size.*")
+}
+
+func TestCiphertextHomomorphism(t *testing.T) {
+	c := qt.New(t)
+	table, err := elgamal.NewDLogTable(20)
+	c.Assert(err, qt.IsNil)
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	encrypt := func(m, k int64) *elgamal.Ciphertext {
+		c1, c2, err := elgamal.Encrypt(big.NewInt(m), pubX, pubY, big.NewInt(k))
+		c.Assert(err, qt.IsNil)
+		return elgamal.NewCiphertext(c1, c2)
+	}
+	decrypt := func(ct *elgamal.Ciphertext) uint64 {
+		m, err := ct.Decrypt(priv, table)
+		c.Assert(err, qt.IsNil)
+		return m
+	}
+	a, b := encrypt(7, 11), encrypt(5, 13)
+
+	sum, err := a.Add(b)
+	c.Assert(err, qt.IsNil)
+	c.Assert(decrypt(sum), qt.Equals, uint64(12))
+	c.Assert(sum.Equal(encrypt(12, 24)), qt.IsTrue)
+
+	diff, err := a.Sub(b)
+	c.Assert(err, qt.IsNil)
+	c.Assert(decrypt(diff), qt.Equals, uint64(2))
+
+	scaled, err := a.Mul(big.NewInt(9))
+	c.Assert(err, qt.IsNil)
+	c.Assert(decrypt(scaled), qt.Equals, uint64(63))
+	c.Assert(scaled.Equal(encrypt(63, 99)), qt.IsTrue)
+
+	zero, err := a.Add(elgamal.NewZeroCiphertext())
+	c.Assert(err, qt.IsNil)
+	c.Assert(zero.Equal(a), qt.IsTrue)
+
+	// RTE round trip
+	c1, c2 := a.RTE()
+	c.Assert(elgamal.CiphertextFromRTE(c1, c2).Equal(a), qt.IsTrue)
+
+	_, err = a.Add(elgamal.NewCiphertext(a.C1, [2]*big.Int{big.NewInt(1), big.NewInt(2)}))
+	c.Assert(err, qt.ErrorMatches, "invalid C2: point is not on the BabyJubJub curve")
+	_, err = a.Mul(big.NewInt(-1))
+	c.Assert(err, qt.ErrorMatches, "invalid scalar")
+}
+
+func TestCiphertextRerandomize(t *testing.T) {
+	c := qt.New(t)
+	table, err := elgamal.NewDLogTable(20)
+	c.Assert(err, qt.IsNil)
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	c1, c2, err := elgamal.Encrypt(big.NewInt(42), pubX, pubY, big.NewInt(5))
+	c.Assert(err, qt.IsNil)
+	in := elgamal.NewCiphertext(c1, c2)
+
+	// re-encrypting with r is encrypting with k + r
+	out, err := in.Rerandomize(pubX, pubY, big.NewInt(7))
+	c.Assert(err, qt.IsNil)
+	c1, c2, err = elgamal.Encrypt(big.NewInt(42), pubX, pubY, big.NewInt(12))
+	c.Assert(err, qt.IsNil)
+	c.Assert(out.Equal(elgamal.NewCiphertext(c1, c2)), qt.IsTrue)
+
+	out, proof, err := in.RerandomizeWithProof(pubX, pubY, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(out.Equal(in), qt.IsFalse)
+	m, err := out.Decrypt(priv, table)
+	c.Assert(err, qt.IsNil)
+	c.Assert(m, qt.Equals, uint64(42))
+	c.Assert(elgamal.VerifyRerandomization(pubX, pubY, in, out, proof), qt.IsNil)
+
+	// a ciphertext of another message does not verify
+	c1, c2, err = elgamal.Encrypt(big.NewInt(1), pubX, pubY, big.NewInt(0))
+	c.Assert(err, qt.IsNil)
+	other, err := out.Add(elgamal.NewCiphertext(c1, c2))
+	c.Assert(err, qt.IsNil)
+	c.Assert(elgamal.VerifyRerandomization(pubX, pubY, in, other, proof), qt.ErrorMatches, "invalid rerandomization: invalid proof")
+
+	// nor does a re-encryption under another key
+	_, otherX, otherY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(elgamal.VerifyRerandomization(otherX, otherY, in, out, proof), qt.ErrorMatches, "invalid rerandomization: invalid proof")
+	forged, err := in.Rerandomize(otherX, otherY, big.NewInt(3))
+	c.Assert(err, qt.IsNil)
+	c.Assert(elgamal.VerifyRerandomization(pubX, pubY, in, forged, proof), qt.ErrorMatches, "invalid rerandomization: invalid proof")
+
+	tampered := *proof
+	tampered.Z = new(big.Int).Add(proof.Z, big.NewInt(1))
+	c.Assert(elgamal.VerifyRerandomization(pubX, pubY, in, out, &tampered), qt.ErrorMatches, "invalid rerandomization: invalid proof")
+	tampered.Z = nil
+	c.Assert(elgamal.VerifyRerandomization(pubX, pubY, in, out, &tampered), qt.ErrorMatches, "invalid rerandomization: missing proof value")
+
+	_, err = in.Rerandomize(big.NewInt(0), big.NewInt(1), big.NewInt(7))
+	c.Assert(err, qt.ErrorMatches, "invalid public key: identity point")
+}
+
+func TestDecryptionProof(t *testing.T) {
+	c := qt.New(t)
+	table, err := elgamal.NewDLogTable(16)
+	c.Assert(err, qt.IsNil)
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	c1, c2, err := elgamal.Encrypt(big.NewInt(1234), pubX, pubY, big.NewInt(77))
+	c.Assert(err, qt.IsNil)
+	ct := elgamal.NewCiphertext(c1, c2)
+
+	m, proof, err := ct.DecryptWithProof(priv, table, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(m, qt.Equals, uint64(1234))
+	c.Assert(elgamal.VerifyDecryption(pubX, pubY, ct, m, proof), qt.IsNil)
+
+	// wrong results, ciphertexts or keys are rejected
+	c.Assert(elgamal.VerifyDecryption(pubX, pubY, ct, m+1, proof), qt.ErrorMatches, "invalid decryption: invalid proof")
+	other, err := ct.Rerandomize(pubX, pubY, big.NewInt(1))
+	c.Assert(err, qt.IsNil)
+	c.Assert(elgamal.VerifyDecryption(pubX, pubY, other, m, proof), qt.ErrorMatches, "invalid decryption: invalid proof")
+	_, otherX, otherY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(elgamal.VerifyDecryption(otherX, otherY, ct, m, proof), qt.ErrorMatches, "invalid decryption: invalid proof")
+
+	// the proof commitments must be curve points
+	tampered := *proof
+	tampered.A = [2]*big.Int{big.NewInt(1), big.NewInt(1)}
+	c.Assert(elgamal.VerifyDecryption(pubX, pubY, ct, m, &tampered), qt.ErrorMatches, "invalid decryption: invalid proof commitment: .*")
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/elgamal"
+)
+
+func TestThresholdElGamal(t *testing.T) {
+	c := qt.New(t)
+	params := elgamal.ThresholdParams{Threshold: 3, Participants: 5}
+
+	// key generation: every participant deals shares to all the others
+	dealers := make([]*elgamal.Dealer, params.Participants)
+	commitments := make([][][2]*big.Int, params.Participants)
+	for i := range dealers {
+		var err error
+		dealers[i], err = elgamal.NewDealer(params, i+1, nil)
+		c.Assert(err, qt.IsNil)
+		commitments[i] = dealers[i].Commitments()
+		c.Assert(commitments[i], qt.HasLen, params.Threshold)
+	}
+	keyShares := make([]*elgamal.KeyShare, params.Participants)
+	verificationKeys := make([][2]*big.Int, params.Participants)
+	for j := 1; j <= params.Participants; j++ {
+		shares := make([]*big.Int, len(dealers))
+		for i, d := range dealers {
+			share, err := d.Share(j)
+			c.Assert(err, qt.IsNil)
+			c.Assert(params.VerifyShare(j, share, commitments[i]), qt.IsNil)
+			shares[i] = share
+		}
+		var err error
+		keyShares[j-1], err = params.NewKeyShare(j, shares)
+		c.Assert(err, qt.IsNil)
+		verificationKeys[j-1], err = params.VerificationKey(j, commitments)
+		c.Assert(err, qt.IsNil)
+	}
+	pubX, pubY, err := params.JointPublicKey(commitments)
+	c.Assert(err, qt.IsNil)
+	c.Assert(onCircomCurve(pubX, pubY), qt.IsTrue)
+
+	// the joint key encrypts ballots and decrypts their tally
+	acc := ballot.NewAccumulator()
+	for _, fields := range [][]uint64{{1, 2, 3}, {3, 0, 1}} {
+		inputs, err := ballot.BuildInputs(&ballot.Ballot{
+			Mode:          ballot.Mode{NumFields: 3, MaxValue: 3},
+			ProcessID:     big.NewInt(1),
+			Address:       big.NewInt(2),
+			Weight:        1,
+			EncryptionKey: [2]*big.Int{pubX, pubY},
+			Fields:        fields,
+		})
+		c.Assert(err, qt.IsNil)
+		c.Assert(acc.Add(inputs.Cipherfields), qt.IsNil)
+	}
+	table, err := elgamal.NewDLogTable(16)
+	c.Assert(err, qt.IsNil)
+	tally := acc.Fields()
+	partials := make([]*elgamal.PartialDecryption, params.Participants)
+	for j, share := range keyShares {
+		partials[j], err = share.PartialDecrypt(tally[2], nil)
+		c.Assert(err, qt.IsNil)
+		c.Assert(elgamal.VerifyPartialDecryption(tally[2], verificationKeys[j], partials[j]), qt.IsNil)
+	}
+	for _, subset := range [][]int{{0, 1, 2}, {1, 3, 4}, {4, 0, 2, 3}, {0, 1, 2, 3, 4}} {
+		var selected []*elgamal.PartialDecryption
+		for _, j := range subset {
+			selected = append(selected, partials[j])
+		}
+		m, err := params.Decrypt(tally[2], selected, table)
+		c.Assert(err, qt.IsNil)
+		c.Assert(m, qt.Equals, uint64(4))
+	}
+
+	// fewer than threshold shares, or repeated ones, cannot decrypt
+	_, err = params.Decrypt(tally[2], partials[:2], table)
+	c.Assert(err, qt.ErrorMatches, "not enough partial decryptions: got 2, want 3")
+	_, err = params.Decrypt(tally[2], []*elgamal.PartialDecryption{partials[0], partials[1], partials[0]}, table)
+	c.Assert(err, qt.ErrorMatches, "duplicated partial decryption of participant 1")
+
+	// a share from another ciphertext or key share does not verify
+	c.Assert(elgamal.VerifyPartialDecryption(tally[1], verificationKeys[0], partials[0]), qt.ErrorMatches, "invalid partial decryption 1: invalid proof")
+	c.Assert(elgamal.VerifyPartialDecryption(tally[2], verificationKeys[1], partials[0]), qt.ErrorMatches, "invalid partial decryption 1: invalid proof")
+
+	// shares not matching the dealer commitments are rejected
+	share, err := dealers[0].Share(2)
+	c.Assert(err, qt.IsNil)
+	c.Assert(params.VerifyShare(3, share, commitments[0]), qt.ErrorMatches, "share does not match the dealer commitments")
+	c.Assert(params.VerifyShare(2, share, commitments[1]), qt.ErrorMatches, "share does not match the dealer commitments")
+	c.Assert(params.VerifyShare(2, share, commitments[0][:2]), qt.ErrorMatches, "invalid number of commitments: got 2, want 3")
+
+	// commitments with a torsion component are rejected: adding the point
+	// (0, -1) of order 2 maps (x, y) to (-x, -y)
+	torsion := append([][2]*big.Int{}, commitments[0]...)
+	torsion[0] = [2]*big.Int{
+		new(big.Int).Sub(fr.Modulus(), torsion[0][0]),
+		new(big.Int).Sub(fr.Modulus(), torsion[0][1]),
+	}
+	c.Assert(params.VerifyShare(2, share, torsion), qt.ErrorMatches, "invalid commitment 0: point is not in the prime-order subgroup")
+	_, _, err = params.JointPublicKey([][][2]*big.Int{torsion, commitments[1]})
+	c.Assert(err, qt.ErrorMatches, "dealer 0: invalid commitment 0: point is not in the prime-order subgroup")
+	_, err = params.VerificationKey(2, [][][2]*big.Int{commitments[1], torsion})
+	c.Assert(err, qt.ErrorMatches, "dealer 1: invalid commitment 0: point is not in the prime-order subgroup")
+
+	_, err = elgamal.NewDealer(elgamal.ThresholdParams{Threshold: 4, Participants: 3}, 1, nil)
+	c.Assert(err, qt.ErrorMatches, "invalid threshold parameters: threshold 4 of 3 participants")
+	_, err = dealers[0].Share(6)
+	c.Assert(err, qt.ErrorMatches, "invalid participant index: got 6, want 1 to 5")
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/circom2gnark"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+func TestCircomFflonkProof(t *testing.T) {
+	c := qt.New(t)
+	fixture, err := testutils.ReadSnarkJSFixture("fflonk")
+	c.Assert(err, qt.IsNil)
+	vkeyBytes, proofJSON, pubSignals := fixture.Vkey, fixture.Proof, fixture.PubSignals
+
+	ok, err := circom2gnark.VerifyCircomFflonkProofBN254(vkeyBytes, proofJSON, pubSignals)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+
+	// the protocol field selects the FFLONK verifier
+	ok, err = circom2gnark.VerifyCircomProof(vkeyBytes, proofJSON, pubSignals)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+
+	// an FFLONK proof is not a PLONK proof
+	_, err = circom2gnark.VerifyCircomPlonkProofBN254(vkeyBytes, proofJSON, pubSignals)
+	c.Assert(err, qt.ErrorMatches, "unexpected protocol.*")
+
+	// unknown protocols are rejected
+	var vk map[string]any
+	c.Assert(json.Unmarshal(vkeyBytes, &vk), qt.IsNil)
+	vk["protocol"] = "marlin"
+	unknown, err := json.Marshal(vk)
+	c.Assert(err, qt.IsNil)
+	_, err = circom2gnark.VerifyCircomProof(unknown, proofJSON, pubSignals)
+	c.Assert(err, qt.ErrorMatches, "unsupported protocol: marlin")
+
+	// a changed public signal, in every position
+	for i := range pubSignals {
+		badSignals := append([]string{}, pubSignals...)
+		badSignals[i] = "6"
+		ok, err = circom2gnark.VerifyCircomFflonkProofBN254(vkeyBytes, proofJSON, badSignals)
+		c.Assert(err, qt.IsNotNil, qt.Commentf("signal %d", i))
+		c.Assert(ok, qt.IsFalse)
+	}
+
+	// tampered evaluation
+	proof, err := circom2gnark.UnmarshalCircomFflonkProofJSON([]byte(proofJSON))
+	c.Assert(err, qt.IsNil)
+	proof.Evaluations.Zw = "1"
+	tampered, err := json.Marshal(proof)
+	c.Assert(err, qt.IsNil)
+	ok, err = circom2gnark.VerifyCircomFflonkProofBN254(vkeyBytes, string(tampered), pubSignals)
+	c.Assert(err, qt.IsNotNil)
+	c.Assert(ok, qt.IsFalse)
+
+	// invalid roots of unity in the verification key
+	circomVk, err := circom2gnark.UnmarshalCircomFflonkVerificationKeyJSON(vkeyBytes)
+	c.Assert(err, qt.IsNil)
+	circomVk.W3 = "1"
+	proof, err = circom2gnark.UnmarshalCircomFflonkProofJSON([]byte(proofJSON))
+	c.Assert(err, qt.IsNil)
+	_, err = proof.VerifyBN254(circomVk, pubSignals)
+	c.Assert(err, qt.ErrorMatches, "w3 is not a primitive cube root of unity")
+}
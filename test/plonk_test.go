@@ -0,0 +1,62 @@
+package test
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/circom2gnark"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+func TestCircomPlonkProof(t *testing.T) {
+	c := qt.New(t)
+	fixture, err := testutils.ReadSnarkJSFixture("plonk")
+	c.Assert(err, qt.IsNil)
+	vkeyBytes, proofJSON, pubSignals := fixture.Vkey, fixture.Proof, fixture.PubSignals
+
+	ok, err := circom2gnark.VerifyCircomPlonkProofBN254(vkeyBytes, proofJSON, pubSignals)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+
+	// the protocol field selects the PLONK verifier
+	ok, err = circom2gnark.VerifyCircomProof(vkeyBytes, proofJSON, pubSignals)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+
+	// Groth16 conversions must reject PLONK keys
+	err = circom2gnark.Circom2SolidityVerifierBN254(vkeyBytes, io.Discard)
+	c.Assert(err, qt.ErrorMatches, "unsupported protocol: plonk.*")
+
+	// wrong public signal
+	badSignals := []string{pubSignals[0], pubSignals[1], "6"}
+	ok, err = circom2gnark.VerifyCircomPlonkProofBN254(vkeyBytes, proofJSON, badSignals)
+	c.Assert(err, qt.IsNotNil)
+	c.Assert(ok, qt.IsFalse)
+
+	// missing public signal
+	_, err = circom2gnark.VerifyCircomPlonkProofBN254(vkeyBytes, proofJSON, pubSignals[:2])
+	c.Assert(err, qt.ErrorMatches, "invalid number of public inputs.*")
+
+	// tampered evaluation
+	proof, err := circom2gnark.UnmarshalCircomPlonkProofJSON([]byte(proofJSON))
+	c.Assert(err, qt.IsNil)
+	proof.EvalA = "1"
+	tampered, err := json.Marshal(proof)
+	c.Assert(err, qt.IsNil)
+	ok, err = circom2gnark.VerifyCircomPlonkProofBN254(vkeyBytes, string(tampered), pubSignals)
+	c.Assert(err, qt.IsNotNil)
+	c.Assert(ok, qt.IsFalse)
+
+	// proof verified with a key for another circuit
+	circomVk, err := circom2gnark.UnmarshalCircomPlonkVerificationKeyJSON(vkeyBytes)
+	c.Assert(err, qt.IsNil)
+	circomVk.Qm, circomVk.Qo = circomVk.Qo, circomVk.Qm
+	otherVkey, err := json.Marshal(circomVk)
+	c.Assert(err, qt.IsNil)
+	ok, err = circom2gnark.VerifyCircomPlonkProofBN254(otherVkey, proofJSON, pubSignals)
+	c.Assert(err, qt.IsNotNil)
+	c.Assert(ok, qt.IsFalse)
+}
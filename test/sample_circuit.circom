@@ -0,0 +1,17 @@
+pragma circom 2.1.0;
+
+// Sample exposes the public signals of the ballot proof (address, vote_id,
+// inputs_hash) and accepts any values for them. It is used to generate the
+// snarkjs fixtures in test/testdata.
+template Sample() {
+    signal input address;
+    signal input vote_id;
+    signal input inputs_hash;
+
+    signal m;
+    signal product;
+    m <== address * vote_id;
+    product <== m * inputs_hash;
+}
+
+component main {public [address, vote_id, inputs_hash]} = Sample();
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/circom2gnark"
+	"github.com/vocdoni/davinci-circom/test/testutils"
+)
+
+func TestSolidityVerifierBN254(t *testing.T) {
+	c := qt.New(t)
+	c.Run("sample", func(c *qt.C) {
+		prover, err := testutils.NewSampleProver(ecc.BN254)
+		c.Assert(err, qt.IsNil)
+		vkeyBytes, err := prover.VerificationKey()
+		c.Assert(err, qt.IsNil)
+		proofJSON, pubJSON, err := prover.Prove(big.NewInt(11), big.NewInt(22), big.NewInt(33))
+		c.Assert(err, qt.IsNil)
+		checkSolidityVerifierBN254(c, vkeyBytes, proofJSON, pubJSON)
+	})
+	c.Run("snarkjs", func(c *qt.C) {
+		fixture, err := testutils.ReadSnarkJSFixture("groth16")
+		c.Assert(err, qt.IsNil)
+		checkSolidityVerifierBN254(c, fixture.Vkey, fixture.Proof, fixture.Public)
+	})
+}
+
+// checkSolidityVerifierBN254 exports the verifier and calldata of a Groth16
+// proof and checks the proof decoded back from the calldata natively.
+func checkSolidityVerifierBN254(c *qt.C, vkeyBytes []byte, proofJSON, pubJSON string) {
+	pubSignals, err := circom2gnark.UnmarshalCircomPublicSignalsJSON([]byte(pubJSON))
+	c.Assert(err, qt.IsNil)
+
+	ok, err := circom2gnark.VerifyCircomProofBN254(vkeyBytes, proofJSON, pubSignals)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+
+	var contract bytes.Buffer
+	err = circom2gnark.Circom2SolidityVerifierBN254(vkeyBytes, &contract)
+	c.Assert(err, qt.IsNil)
+	c.Assert(strings.Contains(contract.String(), "function verifyProof("), qt.IsTrue)
+	c.Assert(strings.Contains(contract.String(), "uint256[3] calldata input"), qt.IsTrue)
+
+	calldata, err := circom2gnark.Circom2SolidityCalldataBN254(proofJSON, pubJSON)
+	c.Assert(err, qt.IsNil)
+	packed, err := calldata.Pack()
+	c.Assert(err, qt.IsNil)
+	c.Assert(packed, qt.HasLen, 4+32*(8+3))
+	// keccak256("verifyProof(uint256[8],uint256[3])")[:4]
+	c.Assert(hex.EncodeToString(packed[:4]), qt.Equals, "65c03259")
+
+	// decode the calldata words back into a gnark proof and check it natively
+	words := packed[4:]
+	var decoded groth16_bn254.Proof
+	_, err = decoded.Ar.SetBytes(words[0:64])
+	c.Assert(err, qt.IsNil)
+	_, err = decoded.Bs.SetBytes(words[64:192])
+	c.Assert(err, qt.IsNil)
+	_, err = decoded.Krs.SetBytes(words[192:256])
+	c.Assert(err, qt.IsNil)
+	inputs := make([]bn254fr.Element, 3)
+	for i := range inputs {
+		inputs[i].SetBytes(words[(8+i)*32 : (9+i)*32])
+		c.Assert(inputs[i].String(), qt.Equals, pubSignals[i])
+	}
+
+	circomVk, err := circom2gnark.UnmarshalCircomVerificationKeyJSON(vkeyBytes)
+	c.Assert(err, qt.IsNil)
+	gnarkVk, err := circomVk.ToGnarkBN254()
+	c.Assert(err, qt.IsNil)
+	c.Assert(groth16_bn254.Verify(&decoded, gnarkVk, inputs), qt.IsNil)
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	twistededwardsbn254 "github.com/consensys/gnark-crypto/ecc/bn254/twistededwards"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/consensys/gnark/test"
+	qt "github.com/frankban/quicktest"
+
+	"github.com/vocdoni/davinci-circom/ballot"
+	"github.com/vocdoni/davinci-circom/elgamal"
+	"github.com/vocdoni/davinci-circom/tally"
+)
+
+func TestTallyDecryptionCircuit(t *testing.T) {
+	c := qt.New(t)
+	table, err := elgamal.NewDLogTable(16)
+	c.Assert(err, qt.IsNil)
+	priv, pubX, pubY, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	key := [2]*big.Int{pubX, pubY}
+
+	acc := ballot.NewAccumulator()
+	for _, fields := range [][]uint64{{3, 1, 0, 2}, {1, 1, 1, 1}} {
+		inputs, err := ballot.BuildInputs(&ballot.Ballot{
+			Mode:          ballot.Mode{NumFields: len(fields), MaxValue: 3},
+			ProcessID:     big.NewInt(1),
+			Address:       big.NewInt(2),
+			Weight:        1,
+			EncryptionKey: key,
+			Fields:        fields,
+		})
+		c.Assert(err, qt.IsNil)
+		c.Assert(acc.Add(inputs.Cipherfields), qt.IsNil)
+	}
+	results, err := acc.Decrypt(priv, table)
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.DeepEquals, []uint64{4, 2, 1, 3, 0, 0, 0, 0})
+
+	circuit := tally.NewDecryptionCircuit(ballot.NFields)
+	assignment, err := tally.NewDecryptionAssignment(priv, key, acc.Cipherfields(), results)
+	c.Assert(err, qt.IsNil)
+	c.Assert(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()), qt.IsNil)
+
+	// wrong results
+	forged := append([]uint64{}, results...)
+	forged[3]++
+	assignment, err = tally.NewDecryptionAssignment(priv, key, acc.Cipherfields(), forged)
+	c.Assert(err, qt.IsNil)
+	c.Assert(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()), qt.IsNotNil)
+
+	// results are range checked, as result + ℓ is the same scalar of G
+	order := twistededwardsbn254.GetEdwardsCurve().Order
+	assignment, err = tally.NewDecryptionAssignment(priv, key, acc.Cipherfields(), results)
+	c.Assert(err, qt.IsNil)
+	assignment.Results[3] = new(big.Int).Add(new(big.Int).SetUint64(results[3]), &order)
+	c.Assert(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()), qt.IsNotNil)
+	assignment.Results[3] = new(big.Int).Lsh(big.NewInt(1), tally.ResultBits)
+	c.Assert(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()), qt.IsNotNil)
+
+	// a private key not matching the encryption key
+	other, _, _, err := elgamal.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	assignment, err = tally.NewDecryptionAssignment(other, key, acc.Cipherfields(), results)
+	c.Assert(err, qt.IsNil)
+	c.Assert(test.IsSolved(circuit, assignment, ecc.BN254.ScalarField()), qt.IsNotNil)
+
+	_, err = tally.NewDecryptionAssignment(priv, key, acc.Cipherfields(), results[:2])
+	c.Assert(err, qt.ErrorMatches, "invalid number of results: got 2, want 8")
+
+	// Groth16 proof with the cipherfields and results as public inputs
+	ccs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, circuit)
+	c.Assert(err, qt.IsNil)
+	pk, vk, err := groth16.Setup(ccs)
+	c.Assert(err, qt.IsNil)
+	assignment, err = tally.NewDecryptionAssignment(priv, key, acc.Cipherfields(), results)
+	c.Assert(err, qt.IsNil)
+	wit, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField())
+	c.Assert(err, qt.IsNil)
+	proof, err := groth16.Prove(ccs, pk, wit)
+	c.Assert(err, qt.IsNil)
+	pubWit, err := wit.Public()
+	c.Assert(err, qt.IsNil)
+	c.Assert(groth16.Verify(proof, vk, pubWit), qt.IsNil)
+
+	// the proof does not verify other results
+	assignment, err = tally.NewDecryptionAssignment(priv, key, acc.Cipherfields(), forged)
+	c.Assert(err, qt.IsNil)
+	forgedWit, err := frontend.NewWitness(assignment, ecc.BN254.ScalarField(), frontend.PublicOnly())
+	c.Assert(err, qt.IsNil)
+	c.Assert(groth16.Verify(proof, vk, forgedWit), qt.IsNotNil)
+}
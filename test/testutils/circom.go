@@ -0,0 +1,176 @@
+package testutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark/backend/groth16"
+	groth16_bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	groth16_bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// sampleCircuit exposes the same public signals as the ballot proof
+// (address, vote_id, inputs_hash) and accepts any values for them. It lets the
+// circom2gnark conversions be exercised without snarkjs or compiled circuits.
+type sampleCircuit struct {
+	Address    frontend.Variable `gnark:",public"`
+	VoteID     frontend.Variable `gnark:",public"`
+	InputsHash frontend.Variable `gnark:",public"`
+	Product    frontend.Variable
+}
+
+func (c *sampleCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(c.Product, api.Mul(c.Address, c.VoteID, c.InputsHash))
+	return nil
+}
+
+// SampleProver generates Groth16 proofs for sampleCircuit and formats them
+// as snarkjs does (proof.json, public.json and verification_key.json).
+type SampleProver struct {
+	curve ecc.ID
+	ccs   constraint.ConstraintSystem
+	pk    groth16.ProvingKey
+	vk    groth16.VerifyingKey
+}
+
+// NewSampleProver compiles the sample circuit over the given curve (BN254 or
+// BLS12-381) and runs a fresh Groth16 setup.
+func NewSampleProver(curve ecc.ID) (*SampleProver, error) {
+	if curve != ecc.BN254 && curve != ecc.BLS12_381 {
+		return nil, fmt.Errorf("unsupported curve %s", curve)
+	}
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, &sampleCircuit{})
+	if err != nil {
+		return nil, err
+	}
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return nil, err
+	}
+	return &SampleProver{curve: curve, ccs: ccs, pk: pk, vk: vk}, nil
+}
+
+// VerificationKey returns the verification key in snarkjs JSON format.
+func (p *SampleProver) VerificationKey() ([]byte, error) {
+	var alpha, ic any
+	var beta, gamma, delta [][]string
+	var curveName string
+	switch vk := p.vk.(type) {
+	case *groth16_bn254.VerifyingKey:
+		curveName = "bn128"
+		points := make([][]string, len(vk.G1.K))
+		for i := range vk.G1.K {
+			points[i] = g1ToCircom(vk.G1.K[i].X.String(), vk.G1.K[i].Y.String())
+		}
+		alpha, ic = g1ToCircom(vk.G1.Alpha.X.String(), vk.G1.Alpha.Y.String()), points
+		beta, gamma, delta = g2ToCircomBN254(&vk.G2.Beta), g2ToCircomBN254(&vk.G2.Gamma), g2ToCircomBN254(&vk.G2.Delta)
+	case *groth16_bls12381.VerifyingKey:
+		curveName = "bls12381"
+		points := make([][]string, len(vk.G1.K))
+		for i := range vk.G1.K {
+			points[i] = g1ToCircom(vk.G1.K[i].X.String(), vk.G1.K[i].Y.String())
+		}
+		alpha, ic = g1ToCircom(vk.G1.Alpha.X.String(), vk.G1.Alpha.Y.String()), points
+		beta, gamma, delta = g2ToCircomBLS12381(&vk.G2.Beta), g2ToCircomBLS12381(&vk.G2.Gamma), g2ToCircomBLS12381(&vk.G2.Delta)
+	default:
+		return nil, fmt.Errorf("unexpected verifying key type %T", p.vk)
+	}
+	return json.Marshal(map[string]any{
+		"protocol":   "groth16",
+		"curve":      curveName,
+		"nPublic":    p.vk.NbPublicWitness(),
+		"vk_alpha_1": alpha,
+		"vk_beta_2":  beta,
+		"vk_gamma_2": gamma,
+		"vk_delta_2": delta,
+		"IC":         ic,
+	})
+}
+
+// Prove returns a proof and its public signals in snarkjs JSON format, in
+// the order of the ballot proof.
+func (p *SampleProver) Prove(address, voteID, inputsHash *big.Int) (string, string, error) {
+	modulus := p.curve.ScalarField()
+	public := []*big.Int{
+		new(big.Int).Mod(address, modulus),
+		new(big.Int).Mod(voteID, modulus),
+		new(big.Int).Mod(inputsHash, modulus),
+	}
+	product := new(big.Int).Mul(public[0], public[1])
+	product.Mul(product, public[2])
+	product.Mod(product, modulus)
+	assignment := &sampleCircuit{
+		Address:    public[0],
+		VoteID:     public[1],
+		InputsHash: public[2],
+		Product:    product,
+	}
+	wit, err := frontend.NewWitness(assignment, modulus)
+	if err != nil {
+		return "", "", err
+	}
+	gnarkProof, err := groth16.Prove(p.ccs, p.pk, wit)
+	if err != nil {
+		return "", "", err
+	}
+	var proof map[string]any
+	switch gp := gnarkProof.(type) {
+	case *groth16_bn254.Proof:
+		proof = map[string]any{
+			"pi_a":  g1ToCircom(gp.Ar.X.String(), gp.Ar.Y.String()),
+			"pi_b":  g2ToCircomBN254(&gp.Bs),
+			"pi_c":  g1ToCircom(gp.Krs.X.String(), gp.Krs.Y.String()),
+			"curve": "bn128",
+		}
+	case *groth16_bls12381.Proof:
+		proof = map[string]any{
+			"pi_a":  g1ToCircom(gp.Ar.X.String(), gp.Ar.Y.String()),
+			"pi_b":  g2ToCircomBLS12381(&gp.Bs),
+			"pi_c":  g1ToCircom(gp.Krs.X.String(), gp.Krs.Y.String()),
+			"curve": "bls12381",
+		}
+	default:
+		return "", "", fmt.Errorf("unexpected proof type %T", gnarkProof)
+	}
+	proof["protocol"] = "groth16"
+	proofJSON, err := json.Marshal(proof)
+	if err != nil {
+		return "", "", err
+	}
+	publicJSON, err := json.Marshal([]string{public[0].String(), public[1].String(), public[2].String()})
+	if err != nil {
+		return "", "", err
+	}
+	return string(proofJSON), string(publicJSON), nil
+}
+
+// g1ToCircom formats G1 coordinates as snarkjs projective decimal strings.
+func g1ToCircom(x, y string) []string {
+	return []string{x, y, "1"}
+}
+
+// g2ToCircomBN254 formats a G2 point as snarkjs projective decimal strings,
+// with the real part of every coordinate first.
+func g2ToCircomBN254(p *bn254.G2Affine) [][]string {
+	return [][]string{
+		{p.X.A0.String(), p.X.A1.String()},
+		{p.Y.A0.String(), p.Y.A1.String()},
+		{"1", "0"},
+	}
+}
+
+// g2ToCircomBLS12381 is the BLS12-381 version of g2ToCircomBN254.
+func g2ToCircomBLS12381(p *bls12381.G2Affine) [][]string {
+	return [][]string{
+		{p.X.A0.String(), p.X.A1.String()},
+		{p.Y.A0.String(), p.Y.A1.String()},
+		{"1", "0"},
+	}
+}
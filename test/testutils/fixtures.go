@@ -0,0 +1,50 @@
+package testutils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vocdoni/davinci-circom/circom2gnark"
+)
+
+// FixturesDir holds the snarkjs proofs written by generate-fixtures.sh, one
+// directory per fixture.
+const FixturesDir = "test/testdata"
+
+// SnarkJSFixture is a snarkjs proof of test/sample_circuit.circom with its
+// public signals (11, 22, 33) and verification key.
+type SnarkJSFixture struct {
+	Vkey       []byte
+	Proof      string
+	Public     string
+	PubSignals []string
+}
+
+// ReadSnarkJSFixture reads the verification_key.json, proof.json and
+// public.json of the named fixture.
+func ReadSnarkJSFixture(name string) (*SnarkJSFixture, error) {
+	root, err := FindRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(root, FixturesDir, name)
+	files := make(map[string][]byte)
+	for _, f := range []string{"verification_key.json", "proof.json", "public.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, f))
+		if err != nil {
+			return nil, fmt.Errorf("missing fixture %s (run generate-fixtures.sh first): %w", name, err)
+		}
+		files[f] = data
+	}
+	pubSignals, err := circom2gnark.UnmarshalCircomPublicSignalsJSON(files["public.json"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", name, err)
+	}
+	return &SnarkJSFixture{
+		Vkey:       files["verification_key.json"],
+		Proof:      string(files["proof.json"]),
+		Public:     string(files["public.json"]),
+		PubSignals: pubSignals,
+	}, nil
+}